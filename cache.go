@@ -0,0 +1,93 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WithCacheDir makes GetMembersCached store leaderboards as JSON files in dir.
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		c.cacheDir = dir
+	}
+}
+
+// WithCacheTTL sets how long leaderboards stored by GetMembersCached stay fresh. It defaults to
+// DefaultMinInterval.
+func WithCacheTTL(d time.Duration) Option {
+	return func(c *Client) {
+		c.cacheTTL = d
+	}
+}
+
+// GetMembersCached is like GetMembers but first looks for the leaderboard in the Client's cache
+// directory, with a separate file per session cookie. A fresh cached copy is used as is; otherwise
+// the leaderboard is fetched and written to the cache. Unreadable, corrupt or expired cache files
+// are ignored. Without a cache directory, it behaves like GetMembers.
+func (c *Client) GetMembersCached(ctx context.Context, lbID int, cookie string, year int, sorted LeaderboardSort) ([]Member, error) {
+	c.init()
+	if c.cacheDir == "" {
+		return c.GetMembers(ctx, lbID, cookie, year, sorted)
+	}
+
+	key, _, err := newLeaderboardKey(year, lbID, cookie)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.cacheDir, fmt.Sprintf("%d-%d-%x.json", key.year, key.id, key.session))
+	if lb, ok := c.readCache(path); ok {
+		return c.members(lb, sorted), nil
+	}
+	res, err := c.getLeaderboard(ctx, year, lbID, cookie)
+	if err != nil {
+		return nil, err
+	}
+	// An unmodified result may have been fetched long ago, so rewriting the file would make stale
+	// data look fresh.
+	if res.modified {
+		if err := writeCache(path, res.raw); err != nil {
+			return nil, fmt.Errorf("could not cache leaderboard: %w", err)
+		}
+	}
+	return c.members(res.lb, sorted), nil
+}
+
+// readCache returns the leaderboard cached at path, if it exists, is still fresh and parses.
+func (c *Client) readCache(path string) (*Leaderboard, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) >= c.cacheTTL {
+		return nil, false
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	lb, err := c.parse(b)
+	if err != nil {
+		return nil, false
+	}
+	return lb, true
+}
+
+// writeCache atomically replaces the file at path with raw.
+func writeCache(path string, raw []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
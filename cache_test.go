@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheIsPerCookie(t *testing.T) {
+	var requests int32
+	srv := fixtureServer(t, "aaa111", &requests)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	c := NewClient(WithBaseURL(srv.URL), WithCacheDir(dir))
+	if _, err := c.GetMembersCached(ctx, 1234, "aaa111", 2023, SortByLocalScore); err != nil {
+		t.Fatalf("GetMembersCached with valid cookie: %v", err)
+	}
+
+	c = NewClient(WithBaseURL(srv.URL), WithCacheDir(dir))
+	if _, err := c.GetMembersCached(ctx, 1234, "session=aaa111\n", 2023, SortByLocalScore); err != nil {
+		t.Errorf("GetMembersCached with the same cookie, not normalized: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("server received %d requests for the same cookie, want 1", n)
+	}
+	for _, cookie := range []string{"", "bbb222"} {
+		if _, err := c.GetMembersCached(ctx, 1234, cookie, 2023, SortByLocalScore); !errors.Is(err, ErrInvalidCookie) {
+			t.Errorf("GetMembersCached with cookie %q error = %v, want ErrInvalidCookie", cookie, err)
+		}
+	}
+	if _, err := c.GetMembersCached(ctx, 0, "aaa111", 2023, SortByLocalScore); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("GetMembersCached with leaderboard ID 0 error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestCacheIsNotRefreshedByUnmodifiedResult(t *testing.T) {
+	var requests int32
+	srv := fixtureServer(t, "aaa111", &requests)
+	dir := t.TempDir()
+	ctx := context.Background()
+	c := NewClient(WithBaseURL(srv.URL), WithCacheDir(dir), WithCacheTTL(50*time.Millisecond))
+
+	if _, err := c.GetMembersCached(ctx, 1234, "aaa111", 2023, SortByLocalScore); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("cache directory holds %v, %v, want a single file", files, err)
+	}
+	before, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.GetMembersCached(ctx, 1234, "aaa111", 2023, SortByLocalScore); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+	after, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Errorf("cache file modified at %v, then at %v by an unmodified result", before.ModTime(), after.ModTime())
+	}
+}
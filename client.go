@@ -0,0 +1,570 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	resty "gopkg.in/resty.v1"
+)
+
+// Client retrieves private leaderboards from Advent of Code. Use NewClient to create one with
+// custom options; the zero value behaves like a Client created without any, which is what the
+// package-level functions use through DefaultClient.
+type Client struct {
+	once sync.Once
+
+	resty          *resty.Client
+	timeout        time.Duration
+	retries        int
+	retryBaseDelay time.Duration
+	minInterval    time.Duration
+	baseURL        string
+	userAgent      string
+	cacheDir       string
+	cacheTTL       time.Duration
+	strict         bool
+	hideInactive   bool
+	maxMembers     int
+	logf           func(format string, args ...interface{})
+
+	mu        sync.Mutex
+	recent    map[leaderboardKey]recentFetch
+	validated map[leaderboardKey]validatedLeaderboard
+}
+
+// leaderboardKey identifies a private leaderboard of a specific year as seen with a specific
+// session cookie, so results fetched with one cookie are never returned for another.
+type leaderboardKey struct {
+	year    int
+	id      int
+	session [sha256.Size]byte // hash of the normalized session cookie
+}
+
+// newLeaderboardKey validates the arguments of a leaderboard request and returns their key,
+// together with the normalized cookie.
+func newLeaderboardKey(year, lbID int, cookie string) (leaderboardKey, string, error) {
+	if err := validateYear(year); err != nil {
+		return leaderboardKey{}, "", err
+	}
+	if lbID <= 0 {
+		return leaderboardKey{}, "", fmt.Errorf("%w: leaderboard ID %d is not positive", ErrInvalidArgument, lbID)
+	}
+	cookie, err := NormalizeCookie(cookie)
+	if err != nil {
+		return leaderboardKey{}, "", err
+	}
+	return leaderboardKey{year: year, id: lbID, session: sha256.Sum256([]byte(cookie))}, cookie, nil
+}
+
+// fetchResult is a fetched private leaderboard.
+type fetchResult struct {
+	lb       *Leaderboard
+	raw      []byte // the JSON lb was decoded from
+	modified bool   // false if lb was fetched before and has not changed since
+}
+
+// recentFetch records when a leaderboard was last fetched and the result, which has a nil
+// Leaderboard while the request is in flight.
+type recentFetch struct {
+	fetched time.Time
+	result  fetchResult
+}
+
+// validatedLeaderboard is a previously fetched leaderboard with the validators Advent of Code
+// returned for it, used to make conditional requests.
+type validatedLeaderboard struct {
+	etag         string
+	lastModified string
+	result       fetchResult
+}
+
+// DefaultMinInterval is the minimum time between two requests for the same leaderboard, following
+// the Advent of Code guidance not to poll the leaderboard JSON more than once every 15 minutes.
+const DefaultMinInterval = 15 * time.Minute
+
+// DefaultBaseURL is the Advent of Code host that leaderboards are requested from, unless
+// configured otherwise with WithBaseURL.
+const DefaultBaseURL = "https://adventofcode.com"
+
+// DefaultUserAgent identifies this package to Advent of Code, as requested for automated
+// clients, unless configured otherwise with WithUserAgent.
+const DefaultUserAgent = "github.com/michielappelman/leaderboard by michiel@appelman.se"
+
+// DefaultTimeout is the time a Client waits for a leaderboard request to complete, unless
+// configured otherwise with WithTimeout.
+const DefaultTimeout = 30 * time.Second
+
+// maxRetryDelay caps the delay between two attempts of a retried request.
+const maxRetryDelay = time.Minute
+
+// Option configures a Client created with NewClient.
+type Option func(*Client)
+
+// WithHTTPClient makes the Client send its requests through hc, allowing custom transports,
+// proxies, TLS settings or connection pooling. The redirect policy of hc is replaced so redirects
+// are not followed.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.resty = withoutRedirects(resty.NewWithClient(hc))
+	}
+}
+
+// WithRestyClient makes the Client send its requests through an existing resty client. The
+// redirect policy of rc is replaced so redirects are not followed.
+func WithRestyClient(rc *resty.Client) Option {
+	return func(c *Client) {
+		c.resty = withoutRedirects(rc)
+	}
+}
+
+// withoutRedirects makes rc return redirect responses as they are, so checkResponse sees the
+// redirect to the login page Advent of Code answers with for an invalid session cookie. Resty
+// refuses to follow redirects by default, but reports them as errors instead.
+func withoutRedirects(rc *resty.Client) *resty.Client {
+	return rc.SetRedirectPolicy(resty.RedirectPolicyFunc(func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}))
+}
+
+// WithTimeout sets the maximum duration of a single leaderboard request. A zero or negative value
+// disables the timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithRetries makes the Client retry a failed request up to count times, waiting an exponentially
+// growing, jittered delay starting around baseDelay between attempts. Only network errors and
+// 500, 502, 503 and 504 responses are retried; the error of the final attempt is returned.
+func WithRetries(count int, baseDelay time.Duration) Option {
+	return func(c *Client) {
+		c.retries = count
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// WithMinInterval sets the minimum time between two requests for the same leaderboard. Within
+// that interval the Client returns the previously fetched result instead of contacting Advent of
+// Code. A zero or negative value disables the limit, which is mostly useful in tests.
+func WithMinInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.minInterval = d
+	}
+}
+
+// WithBaseURL makes the Client request leaderboards from u instead of DefaultBaseURL, e.g. to use
+// an httptest.Server or a local mirror.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request. Please include a way to
+// contact you, so the Advent of Code maintainers can reach out instead of blocking your traffic.
+func WithUserAgent(s string) Option {
+	return func(c *Client) {
+		c.userAgent = s
+	}
+}
+
+// WithStrictParsing makes the Client parse leaderboards with ParseLeaderboardStrict, so changes in
+// the format served by Advent of Code result in errors instead of silently empty fields.
+func WithStrictParsing(strict bool) Option {
+	return func(c *Client) {
+		c.strict = strict
+	}
+}
+
+// WithMaxMembers makes the Client fail with ErrTooManyMembers for leaderboards with more than n
+// members instead of DefaultMaxMembers, see Parser.MaxMembers. A zero or negative value disables
+// the limit.
+func WithMaxMembers(n int) Option {
+	return func(c *Client) {
+		c.maxMembers = n
+	}
+}
+
+// WithHideInactive makes the Client leave members without any stars out of the Members it
+// returns, instead of listing them at the bottom.
+func WithHideInactive(hide bool) Option {
+	return func(c *Client) {
+		c.hideInactive = hide
+	}
+}
+
+// WithLogger makes the Client report every request it sends to logf, with the URL, the status
+// code and the size of the response, e.g. log.Printf. The session cookie is never logged.
+func WithLogger(logf func(format string, args ...interface{})) Option {
+	return func(c *Client) {
+		c.logf = logf
+	}
+}
+
+// NewClient returns a Client configured with the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{}
+	c.init()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// init applies the default configuration the first time it is called.
+func (c *Client) init() {
+	c.once.Do(func() {
+		c.resty = withoutRedirects(resty.New())
+		c.timeout = DefaultTimeout
+		c.minInterval = DefaultMinInterval
+		c.baseURL = DefaultBaseURL
+		c.userAgent = DefaultUserAgent
+		c.cacheTTL = DefaultMinInterval
+		c.maxMembers = DefaultMaxMembers
+		c.recent = make(map[leaderboardKey]recentFetch)
+		c.validated = make(map[leaderboardKey]validatedLeaderboard)
+	})
+}
+
+// DefaultClient is the Client used by GetMembers and GetMembersContext.
+var DefaultClient = NewClient()
+
+// Fetcher retrieves the Members of private leaderboards. It is implemented by Client; code that
+// depends on a Fetcher instead can be tested with a fake that does not access the network.
+type Fetcher interface {
+	GetMembers(ctx context.Context, lbID int, cookie string, year int, sorted LeaderboardSort) ([]Member, error)
+}
+
+var _ Fetcher = (*Client)(nil)
+
+// GetMembers returns a slice of private leaderboard Members sorted by a sorting function given the
+// private leaderboard ID, a session cookie and the year of the Advent of Code challenge. The
+// request is performed with the given context.
+func (c *Client) GetMembers(ctx context.Context, lbID int, cookie string, year int, sorted LeaderboardSort) ([]Member, error) {
+	members, _, err := c.GetMembersIfModified(ctx, lbID, cookie, year, sorted)
+	return members, err
+}
+
+// GetMembersIfModified is like GetMembers but also reports whether the leaderboard changed since
+// the Client last fetched it. Unchanged leaderboards are not downloaded again: the Client sends a
+// conditional request and returns the Members it already has when Advent of Code answers that the
+// leaderboard was not modified, or when it is requested again within the minimum interval.
+func (c *Client) GetMembersIfModified(ctx context.Context, lbID int, cookie string, year int, sorted LeaderboardSort) ([]Member, bool, error) {
+	c.init()
+	res, err := c.getLeaderboard(ctx, year, lbID, cookie)
+	if err != nil {
+		return nil, false, err
+	}
+	return c.members(res.lb, sorted), res.modified, nil
+}
+
+// members returns the Members of lb sorted like MembersFromLeaderboard, without the inactive ones
+// if the Client is configured to hide them.
+func (c *Client) members(lb *Leaderboard, sorted LeaderboardSort) []Member {
+	members := MembersFromLeaderboard(lb, sorted)
+	if c.hideInactive {
+		members = FilterMembers(members, func(m Member) bool { return m.Stars > 0 })
+	}
+	return members
+}
+
+// GetLeaderboardRaw returns the given private leaderboard both as the JSON returned by Advent of
+// Code and decoded into a Leaderboard, e.g. to archive the original response. Both are copies, so
+// modifying them does not affect what the Client returns later.
+func (c *Client) GetLeaderboardRaw(ctx context.Context, lbID int, cookie string, year int) ([]byte, *Leaderboard, error) {
+	c.init()
+	res, err := c.getLeaderboard(ctx, year, lbID, cookie)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The result may be returned again within the minimum interval, so decode a fresh Leaderboard
+	// from a copy of the JSON instead of handing out the shared one.
+	raw := append([]byte(nil), res.raw...)
+	lb, err := c.parse(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw, lb, nil
+}
+
+// maxConcurrentFetches bounds the number of leaderboards GetMultipleLeaderboards fetches at once.
+const maxConcurrentFetches = 4
+
+// GetMultipleLeaderboards fetches several private leaderboards concurrently, returning the sorted
+// Members of each leaderboard by ID. Leaderboards that could not be fetched are missing from the
+// result and reported in the returned LeaderboardErrors.
+func (c *Client) GetMultipleLeaderboards(ctx context.Context, ids []int, cookie string, year int, sorted LeaderboardSort) (map[int][]Member, error) {
+	c.init()
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[int][]Member)
+		errs    = make(LeaderboardErrors)
+		sem     = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			var members []Member
+			var err error
+			select {
+			case sem <- struct{}{}:
+				members, err = c.GetMembers(ctx, id, cookie, year, sorted)
+				<-sem
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = err
+			} else {
+				results[id] = members
+			}
+		}(id)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
+
+// getLeaderboard returns the given private leaderboard after validating the arguments, enforcing
+// the Client's minimum interval between requests for the same leaderboard with the same cookie.
+// Within that interval the previously fetched result is returned instead, reported as unmodified,
+// or ErrRateLimited if a request is still in flight.
+func (c *Client) getLeaderboard(ctx context.Context, year, lbID int, cookie string) (fetchResult, error) {
+	key, cookie, err := newLeaderboardKey(year, lbID, cookie)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	if c.minInterval <= 0 {
+		return c.fetchLeaderboard(ctx, key, cookie)
+	}
+
+	c.mu.Lock()
+	prev, seen := c.recent[key]
+	if seen && time.Since(prev.fetched) < c.minInterval {
+		c.mu.Unlock()
+		if prev.result.lb == nil {
+			return fetchResult{}, ErrRateLimited
+		}
+		res := prev.result
+		res.modified = false
+		return res, nil
+	}
+	c.recent[key] = recentFetch{fetched: time.Now()}
+	c.mu.Unlock()
+
+	res, err := c.fetchLeaderboard(ctx, key, cookie)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err == nil:
+		c.recent[key] = recentFetch{fetched: time.Now(), result: res}
+	case seen:
+		c.recent[key] = prev
+	default:
+		delete(c.recent, key)
+	}
+	return res, err
+}
+
+// fetchLeaderboard downloads and decodes the given private leaderboard. If the Client fetched it
+// before, the request is made conditional and the earlier result is returned, reported as
+// unmodified, when Advent of Code answers with 304 Not Modified.
+func (c *Client) fetchLeaderboard(ctx context.Context, key leaderboardKey, cookie string) (fetchResult, error) {
+	url := fmt.Sprintf("%s/%d/leaderboard/private/view/%d.json", c.baseURL, key.year, key.id)
+	headers := map[string]string{"Accept": "application/json"}
+	c.mu.Lock()
+	cached, ok := c.validated[key]
+	c.mu.Unlock()
+	if ok {
+		if cached.etag != "" {
+			headers["If-None-Match"] = cached.etag
+		}
+		if cached.lastModified != "" {
+			headers["If-Modified-Since"] = cached.lastModified
+		}
+	}
+
+	resp, err := c.get(ctx, url, cookie, headers)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	if resp.StatusCode() == http.StatusNotModified && ok {
+		res := cached.result
+		res.modified = false
+		return res, nil
+	}
+	lb, err := c.parse(resp.Body())
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("could not parse leaderboard from %s: %w", url, err)
+	}
+	res := fetchResult{lb: lb, raw: resp.Body(), modified: true}
+
+	etag, lastModified := resp.Header().Get("ETag"), resp.Header().Get("Last-Modified")
+	c.mu.Lock()
+	if etag != "" || lastModified != "" {
+		c.validated[key] = validatedLeaderboard{etag: etag, lastModified: lastModified, result: res}
+	} else {
+		delete(c.validated, key)
+	}
+	c.mu.Unlock()
+	return res, nil
+}
+
+// parse decodes a leaderboard, strictly if the Client is configured to, failing as soon as it has
+// more members than the Client accepts.
+func (c *Client) parse(b []byte) (*Leaderboard, error) {
+	return Parser{Strict: c.strict, MaxMembers: c.maxMembers}.Parse(bytes.NewReader(b))
+}
+
+// get requests url with the given headers and returns the response, or an error if the request
+// failed or was not answered successfully.
+func (c *Client) get(ctx context.Context, url, cookie string, headers map[string]string) (*resty.Response, error) {
+	cookie, err := NormalizeCookie(cookie)
+	if err != nil {
+		return nil, err
+	}
+	return c.getPage(ctx, url, cookie, headers)
+}
+
+// getPublic is like get for pages that do not need a login: without a cookie, the request is made
+// without one.
+func (c *Client) getPublic(ctx context.Context, url, cookie string, headers map[string]string) (*resty.Response, error) {
+	if cookie == "" {
+		return c.getPage(ctx, url, "", headers)
+	}
+	return c.get(ctx, url, cookie, headers)
+}
+
+// getPage is get for an already normalized cookie, or none if it is empty.
+func (c *Client) getPage(ctx context.Context, url, cookie string, headers map[string]string) (*resty.Response, error) {
+	resp, err := c.fetch(ctx, url, cookie, headers)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp, url); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkResponse returns an error describing why resp, the response to a request for url, does not
+// contain the requested page, or nil if it does.
+func checkResponse(resp *resty.Response, url string) error {
+	code := resp.StatusCode()
+	switch {
+	case code == http.StatusOK, code == http.StatusNotModified:
+	case code >= 300 && code < 400, code == 400, code == 401, code == 403:
+		return &HTTPError{StatusCode: code, URL: url, Err: ErrInvalidCookie}
+	case code == 404:
+		return &HTTPError{StatusCode: code, URL: url, Err: ErrLeaderboardNotFound}
+	case code == 429:
+		return &HTTPError{StatusCode: code, URL: url, Err: &RateLimitError{retryAfter: parseRetryAfter(resp.Header().Get("Retry-After"))}}
+	case code >= 500:
+		return &HTTPError{StatusCode: code, URL: url, Err: ErrServerError}
+	default:
+		return &HTTPError{StatusCode: code, URL: url}
+	}
+	return nil
+}
+
+// fetch requests url, retrying transient failures according to the Client's retry settings. The
+// response of the last attempt is returned.
+func (c *Client) fetch(ctx context.Context, url, cookie string, headers map[string]string) (*resty.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, url, cookie, headers)
+		if attempt >= c.retries || ctx.Err() != nil || !isRetryable(resp, err) {
+			return resp, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.retryDelay(attempt)):
+		}
+	}
+}
+
+// do performs a single request for url with the given headers.
+func (c *Client) do(ctx context.Context, url, cookie string, headers map[string]string) (*resty.Response, error) {
+	reqCtx := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req := c.resty.R().
+		SetContext(reqCtx).
+		SetHeaders(headers).
+		SetHeader("User-Agent", c.userAgent)
+	if cookie != "" {
+		req.SetHeader("Cookie", fmt.Sprintf("session=%s", cookie))
+	}
+	resp, err := req.Get(url)
+	if err != nil {
+		c.log("GET %s: %v", url, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if reqCtx.Err() == context.DeadlineExceeded || isTimeout(err) {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("could not connect to Advent of Code: %w", err)
+	}
+	c.log("GET %s: %d, %d bytes", url, resp.StatusCode(), len(resp.Body()))
+	return resp, nil
+}
+
+// log reports a request to the logger configured with WithLogger, if any.
+func (c *Client) log(format string, args ...interface{}) {
+	if c.logf != nil {
+		c.logf(format, args...)
+	}
+}
+
+// retryDelay returns the jittered, exponentially growing delay before the retry following the
+// given attempt.
+func (c *Client) retryDelay(attempt int) time.Duration {
+	if c.retryBaseDelay <= 0 {
+		return 0
+	}
+	d := c.retryBaseDelay << uint(attempt)
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// isRetryable reports whether a request that ended with resp and err is worth retrying: network
+// errors and 500, 502, 503 or 504 responses are, anything else is not.
+func isRetryable(resp *resty.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode() {
+	case 500, 502, 503, 504:
+		return true
+	}
+	return false
+}
+
+// isTimeout reports whether err was caused by a network timeout.
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
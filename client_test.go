@@ -0,0 +1,175 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRedirectIsInvalidCookie(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Redirect(w, r, "/2023/auth/login", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithRetries(2, time.Millisecond))
+	_, err := c.GetMembers(context.Background(), 1234, "abc123", 2023, SortByLocalScore)
+	if !errors.Is(err, ErrInvalidCookie) {
+		t.Fatalf("GetMembers error = %v, want ErrInvalidCookie", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
+
+// readFixture returns the contents of the leaderboard fixture in testdata.
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+	b, err := os.ReadFile("testdata/leaderboard.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// fixtureServer serves the leaderboard fixture to requests with the given session cookie, and
+// answers all other requests with 400 Bad Request like Advent of Code does.
+func fixtureServer(t *testing.T, cookie string, requests *int32) *httptest.Server {
+	t.Helper()
+	return leaderboardServer(t, cookie, readFixture(t), requests)
+}
+
+// leaderboardServer is like fixtureServer but serves the given leaderboard JSON.
+func leaderboardServer(t *testing.T, cookie string, fixture []byte, requests *int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if c, err := r.Cookie("session"); err != nil || c.Value != cookie {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRecentResultIsPerCookie(t *testing.T) {
+	var requests int32
+	srv := fixtureServer(t, "aaa111", &requests)
+	c := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	if _, err := c.GetMembers(ctx, 1234, "aaa111", 2023, SortByLocalScore); err != nil {
+		t.Fatalf("GetMembers with valid cookie: %v", err)
+	}
+	if _, err := c.GetMembers(ctx, 1234, "session=aaa111\n", 2023, SortByLocalScore); err != nil {
+		t.Errorf("GetMembers with the same cookie, not normalized: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("server received %d requests for the same cookie, want 1", n)
+	}
+	if _, err := c.GetMembers(ctx, 1234, "bbb222", 2023, SortByLocalScore); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf("GetMembers with another cookie error = %v, want ErrInvalidCookie", err)
+	}
+	if _, err := c.GetMembers(ctx, 1234, "not a cookie", 2023, SortByLocalScore); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf("GetMembers with malformed cookie error = %v, want ErrInvalidCookie", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+}
+
+func TestGetLeaderboardRawReturnsCopy(t *testing.T) {
+	var requests int32
+	srv := fixtureServer(t, "aaa111", &requests)
+	c := NewClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	raw, lb, err := c.GetLeaderboardRaw(ctx, 1234, "aaa111", 2023)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(lb.Members, "101")
+	for i := range raw {
+		raw[i] = ' '
+	}
+
+	members, err := c.GetMembers(ctx, 1234, "aaa111", 2023, SortByLocalScore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 5 || members[0].ID != "101" {
+		t.Errorf("GetMembers after modifying the raw result returned %v", members)
+	}
+}
+
+func TestGetMembersEmptyLeaderboard(t *testing.T) {
+	var requests int32
+	srv := leaderboardServer(t, "aaa111", []byte(`{"owner_id":"1","event":"2023","members":{}}`), &requests)
+	c := NewClient(WithBaseURL(srv.URL))
+
+	members, err := c.GetMembers(context.Background(), 1234, "aaa111", 2023, SortByLocalScore)
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	if members == nil || len(members) != 0 {
+		t.Fatalf("GetMembers = %#v, want an empty, non-nil slice", members)
+	}
+	if s := Summarize(members); !reflect.DeepEqual(s, Summary{}) {
+		t.Errorf("Summarize = %+v, want the zero Summary", s)
+	}
+	if n := CountTotalStars(members); n != 0 {
+		t.Errorf("CountTotalStars = %d, want 0", n)
+	}
+	if avg, med := AverageStars(members), MedianLocalScore(members); avg != 0 || med != 0 {
+		t.Errorf("AverageStars, MedianLocalScore = %v, %v, want 0, 0", avg, med)
+	}
+	if scores := ComputeLocalScores(members); len(scores) != 0 {
+		t.Errorf("ComputeLocalScores = %v, want none", scores)
+	}
+	if days := ActiveDays(members); days == nil || len(days) != 0 {
+		t.Errorf("ActiveDays = %#v, want an empty, non-nil slice", days)
+	}
+	if totals := CumulativeStarsByDay(members, 0); len(totals) != 0 {
+		t.Errorf("CumulativeStarsByDay = %v, want none", totals)
+	}
+}
+
+func TestGetMembersIsDeterministic(t *testing.T) {
+	var requests int32
+	srv := fixtureServer(t, "aaa111", &requests)
+	c := NewClient(WithBaseURL(srv.URL), WithMinInterval(0))
+	ctx := context.Background()
+
+	sorts := []LeaderboardSort{NoSort, SortByLocalScore, SortByGlobalScore, SortByStars, SortByName, SortByLastStar,
+		SortByLocalScoreAsc, SortByGlobalScoreAsc, SortByStarsAsc, SortByLastStarAsc}
+	for _, sorted := range sorts {
+		var first []string
+		for i := 0; i < 50; i++ {
+			members, err := c.GetMembers(ctx, 1234, "aaa111", 2023, sorted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]string, len(members))
+			for j, m := range members {
+				ids[j] = m.ID
+			}
+			if first == nil {
+				first = ids
+			} else if fmt.Sprint(ids) != fmt.Sprint(first) {
+				t.Fatalf("sort %d: GetMembers returned %v, earlier %v", sorted, ids, first)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package leaderboard
+
+// Comparison is the result of comparing two members head to head. Each field holds the ID of the
+// member who leads, or an empty string if they are tied.
+type Comparison struct {
+	Stars       string
+	LocalScore  string
+	GlobalScore string
+	// Days holds, per day on which either member earned a star, the ID of the member who earned
+	// the star of part 1 and of part 2 first. Earning a star at all beats not earning it.
+	Days map[int][2]string
+}
+
+// CompareMembers compares a and b on their stars, scores and, per day, who was first to solve each
+// part.
+func CompareMembers(a, b Member) Comparison {
+	c := Comparison{
+		Stars:       leader(a.ID, a.Stars, b.ID, b.Stars),
+		LocalScore:  leader(a.ID, a.LocalScore, b.ID, b.LocalScore),
+		GlobalScore: leader(a.ID, a.GlobalScore, b.ID, b.GlobalScore),
+		Days:        make(map[int][2]string),
+	}
+	for _, day := range ActiveDays([]Member{a, b}) {
+		var first [2]string
+		for part := 1; part <= 2; part++ {
+			first[part-1] = firstToSolve(a, b, day, part)
+		}
+		c.Days[day] = first
+	}
+	return c
+}
+
+// leader returns the ID of the member with the highest value, or an empty string on a tie.
+func leader(aID string, a int, bID string, b int) string {
+	switch {
+	case a > b:
+		return aID
+	case b > a:
+		return bID
+	}
+	return ""
+}
+
+// firstToSolve returns the ID of the member among a and b who earned the star for the given day
+// and part first, or an empty string if neither did or both did at the same time.
+func firstToSolve(a, b Member, day, part int) string {
+	la, okA := a.level(day, part)
+	lb, okB := b.level(day, part)
+	switch {
+	case okA && (!okB || la.Timestamp.Before(lb.Timestamp.Time)):
+		return a.ID
+	case okB && (!okA || lb.Timestamp.Before(la.Timestamp.Time)):
+		return b.ID
+	}
+	return ""
+}
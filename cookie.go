@@ -0,0 +1,64 @@
+package leaderboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CookieEnv is the environment variable CookieFromEnv reads the session cookie from.
+const CookieEnv = "AOC_SESSION"
+
+// CookieFile is the file, relative to the home directory, that CookieFromFile reads the session
+// cookie from when no path is given.
+const CookieFile = ".adventofcode.session"
+
+// CookieFromEnv returns the session cookie stored in the AOC_SESSION environment variable,
+// normalized with NormalizeCookie.
+func CookieFromEnv() (string, error) {
+	cookie, ok := os.LookupEnv(CookieEnv)
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not set", ErrInvalidCookie, CookieEnv)
+	}
+	return NormalizeCookie(cookie)
+}
+
+// CookieFromFile returns the session cookie stored in the file at path, normalized with
+// NormalizeCookie so e.g. a trailing newline does no harm. A leading ~ in path stands for the home
+// directory, and an empty path means ~/.adventofcode.session.
+func CookieFromFile(path string) (string, error) {
+	if path == "" {
+		path = filepath.Join("~", CookieFile)
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find session cookie file: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read session cookie: %w", err)
+	}
+	return NormalizeCookie(string(b))
+}
+
+// NormalizeCookie returns the session token in s, stripping surrounding whitespace and quotes and
+// a leading "session=" as copied from a browser. It returns an ErrInvalidCookie error if the
+// result does not look like a session token, which consists of hexadecimal digits.
+func NormalizeCookie(s string) (string, error) {
+	cookie := strings.Trim(strings.TrimSpace(s), `"'`)
+	cookie = strings.TrimSpace(strings.TrimPrefix(cookie, "session="))
+	cookie = strings.Trim(cookie, `"'`)
+	if cookie == "" {
+		return "", fmt.Errorf("%w: session cookie is empty", ErrInvalidCookie)
+	}
+	for _, r := range cookie {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return "", fmt.Errorf("%w: session cookie is not a hexadecimal token", ErrInvalidCookie)
+		}
+	}
+	return cookie, nil
+}
@@ -0,0 +1,104 @@
+package leaderboard
+
+import "sort"
+
+// MemberChange describes how a Member changed between two leaderboard snapshots.
+type MemberChange struct {
+	ID               string
+	Name             string
+	StarsDelta       int
+	LocalScoreDelta  int
+	GlobalScoreDelta int
+	Added            bool // the member is not present in the old snapshot
+	Removed          bool // the member is not present in the new snapshot
+	NewlyScoring     bool // the member had no stars in the old snapshot but has some now
+}
+
+// Diff compares two snapshots of the same leaderboard and returns a MemberChange for every member
+// that was added, removed or whose stars or scores changed. Changes follow the order of the new
+// snapshot, followed by removed members in the order of the old one.
+func Diff(old, new []Member) []MemberChange {
+	before := MembersByID(old)
+	var changes []MemberChange
+	for _, m := range new {
+		prev, ok := before[m.ID]
+		change := memberChange(prev, m, ok)
+		if change != (MemberChange{ID: m.ID, Name: m.Name}) {
+			changes = append(changes, change)
+		}
+	}
+	after := MembersByID(new)
+	for _, m := range old {
+		if _, ok := after[m.ID]; !ok {
+			changes = append(changes, MemberChange{
+				ID:               m.ID,
+				Name:             m.Name,
+				StarsDelta:       -m.Stars,
+				LocalScoreDelta:  -m.LocalScore,
+				GlobalScoreDelta: -m.GlobalScore,
+				Removed:          true,
+			})
+		}
+	}
+	return changes
+}
+
+// DetectNewStars compares two snapshots of the same leaderboard and returns a StarEvent for every
+// star present in the new snapshot but not in the old one, ordered by the time it was earned.
+func DetectNewStars(old, new []Member) []StarEvent {
+	before := MembersByID(old)
+	var events []StarEvent
+	for _, m := range new {
+		prev := before[m.ID]
+		for _, e := range m.starEvents() {
+			if !prev.CompletedPart(e.Day, e.Part) {
+				events = append(events, e)
+			}
+		}
+	}
+	sortStarEvents(events)
+	return events
+}
+
+// BiggestMovers returns the changes of the at most n members of the new snapshot who gained the
+// most local score since the old one, biggest gain first and ties ordered by ID. Members that are
+// not present in the old snapshot gain their full local score; members without any gain are left
+// out.
+func BiggestMovers(old, new []Member, n int) []MemberChange {
+	before := MembersByID(old)
+	movers := []MemberChange{}
+	for _, m := range new {
+		prev, ok := before[m.ID]
+		if m.LocalScore-prev.LocalScore <= 0 {
+			continue
+		}
+		movers = append(movers, memberChange(prev, m, ok))
+	}
+	sort.SliceStable(movers, func(i, j int) bool {
+		if movers[i].LocalScoreDelta != movers[j].LocalScoreDelta {
+			return movers[i].LocalScoreDelta > movers[j].LocalScoreDelta
+		}
+		return idLess(movers[i].ID, movers[j].ID)
+	})
+	if n < 0 {
+		n = 0
+	}
+	if n < len(movers) {
+		movers = movers[:n]
+	}
+	return movers
+}
+
+// memberChange returns the change from prev to m, where existed reports whether m is present in
+// the old snapshot at all.
+func memberChange(prev, m Member, existed bool) MemberChange {
+	return MemberChange{
+		ID:               m.ID,
+		Name:             m.Name,
+		StarsDelta:       m.Stars - prev.Stars,
+		LocalScoreDelta:  m.LocalScore - prev.LocalScore,
+		GlobalScoreDelta: m.GlobalScore - prev.GlobalScore,
+		Added:            !existed,
+		NewlyScoring:     prev.Stars == 0 && m.Stars > 0,
+	}
+}
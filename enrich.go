@@ -0,0 +1,140 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// EnrichedMember is a Member together with statistics computed from its completion data. It is
+// encoded as JSON like the Member, with additional fields for the statistics.
+type EnrichedMember struct {
+	Member
+	LongestStreak      int
+	DaysFullyCompleted int
+	// SolveDurations holds, per day, the SolveDuration of part 1 and part 2, zero for parts the
+	// Member did not complete.
+	SolveDurations map[int][2]time.Duration
+	// Data holds whatever else a custom enrichment function computes.
+	Data interface{}
+}
+
+// enrichedStats are the JSON fields EnrichedMember adds to those of its Member.
+type enrichedStats struct {
+	LongestStreak      int                      `json:"longest_streak"`
+	DaysFullyCompleted int                      `json:"days_fully_completed"`
+	SolveDurations     map[int][2]time.Duration `json:"solve_durations"`
+	Data               interface{}              `json:"data,omitempty"`
+}
+
+var enrichedFields = []string{"longest_streak", "days_fully_completed", "solve_durations", "data"}
+
+// String returns the String of the Member followed by its longest streak and the number of days
+// it fully completed, such as "Alice (id=123) ... streak=5 full=4".
+func (e EnrichedMember) String() string {
+	return fmt.Sprintf("%s streak=%d full=%d", e.Member.String(), e.LongestStreak, e.DaysFullyCompleted)
+}
+
+// MarshalJSON is needed because EnrichedMember would otherwise use the method of the embedded
+// Member, leaving out the statistics.
+func (e EnrichedMember) MarshalJSON() ([]byte, error) {
+	member, err := withoutExtra(e.Member, enrichedFields...).MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := json.Marshal(enrichedStats{
+		LongestStreak:      e.LongestStreak,
+		DaysFullyCompleted: e.DaysFullyCompleted,
+		SolveDurations:     e.SolveDurations,
+		Data:               e.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return joinJSONObjects(member, stats), nil
+}
+
+func (e *EnrichedMember) UnmarshalJSON(b []byte) error {
+	var stats enrichedStats
+	if err := json.Unmarshal(b, &stats); err != nil {
+		return err
+	}
+	var m Member
+	if err := m.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	*e = EnrichedMember{
+		Member:             withoutExtra(m, enrichedFields...),
+		LongestStreak:      stats.LongestStreak,
+		DaysFullyCompleted: stats.DaysFullyCompleted,
+		SolveDurations:     stats.SolveDurations,
+		Data:               stats.Data,
+	}
+	return nil
+}
+
+// EnrichMembers calls fn for every member on a pool of workers, one per CPU, and returns the
+// results in the order of members. A nil fn fills in the statistics of EnrichedMember, using
+// year for the solve durations. If ctx is done before every member has been processed,
+// EnrichMembers stops early and returns nil.
+func EnrichMembers(ctx context.Context, members []Member, year int, fn func(Member) EnrichedMember) []EnrichedMember {
+	return EnrichMembersIn(ctx, members, year, eastern, fn)
+}
+
+// EnrichMembersIn is like EnrichMembers but a nil fn measures the solve durations from midnight in
+// loc, see SolveDurationIn.
+func EnrichMembersIn(ctx context.Context, members []Member, year int, loc *time.Location, fn func(Member) EnrichedMember) []EnrichedMember {
+	if fn == nil {
+		fn = func(m Member) EnrichedMember { return enrich(m, year, loc) }
+	}
+	enriched := make([]EnrichedMember, len(members))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				enriched[i] = fn(members[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range members {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indices <- i:
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return enriched
+}
+
+// enrich returns m with the statistics of EnrichedMember computed for the event of year, measuring
+// solve durations from midnight in loc.
+func enrich(m Member, year int, loc *time.Location) EnrichedMember {
+	e := EnrichedMember{
+		Member:             m,
+		LongestStreak:      m.LongestStreak(),
+		DaysFullyCompleted: m.DaysFullyCompleted(),
+		SolveDurations:     make(map[int][2]time.Duration),
+	}
+	for _, day := range m.activeDays() {
+		var durations [2]time.Duration
+		for part := 1; part <= 2; part++ {
+			durations[part-1], _ = m.SolveDurationIn(day, part, year, loc)
+		}
+		e.SolveDurations[day] = durations
+	}
+	return e
+}
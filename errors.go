@@ -0,0 +1,114 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidArgument is returned, before contacting Advent of Code, for arguments that cannot
+	// be valid, such as a year before the first event.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrInvalidCookie is returned when Advent of Code does not accept the session cookie, either
+	// by rejecting the request or by redirecting it to the login page.
+	ErrInvalidCookie = errors.New("invalid or expired session cookie")
+
+	// ErrLeaderboardNotFound is returned when Advent of Code answers with 404 Not Found. For a
+	// private leaderboard this means the ID is wrong or the account of the session cookie is not a
+	// member of it; for a day leaderboard, that the day has not unlocked yet.
+	ErrLeaderboardNotFound = errors.New("leaderboard not found, check the leaderboard ID and that your account is a member")
+
+	// ErrRateLimited is returned when Advent of Code asks us to slow down, or when a leaderboard is
+	// requested again within the Client's minimum interval while no earlier result is available.
+	ErrRateLimited = errors.New("leaderboard requested too soon, try again later")
+
+	// ErrServerError is returned when Advent of Code responds with a server error.
+	ErrServerError = errors.New("Advent of Code server error")
+
+	// ErrTimeout is returned when a request does not complete within the Client's timeout.
+	ErrTimeout = errors.New("request to Advent of Code timed out")
+
+	// ErrTooManyMembers is returned when a leaderboard has more members than the parser accepts,
+	// see Parser.MaxMembers and WithMaxMembers.
+	ErrTooManyMembers = errors.New("leaderboard has too many members")
+)
+
+// HTTPError is returned when Advent of Code answers a request with an unexpected status code. If
+// the status code has a known cause, errors.Is matches the corresponding error, such as
+// ErrInvalidCookie or ErrLeaderboardNotFound.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	Err        error // the known cause of the status code, or nil
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("HTTP status %d from %s", e.StatusCode, e.URL)
+	if e.Err != nil {
+		return e.Err.Error() + ": " + msg
+	}
+	return "error connecting to Advent of Code: " + msg
+}
+
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// RateLimitError is the cause of an HTTPError for a 429 Too Many Requests response, holding the
+// wait Advent of Code asked for in its Retry-After header. It matches ErrRateLimited with errors.Is.
+type RateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.retryAfter > 0 {
+		return fmt.Sprintf("%v, retry after %v", ErrRateLimited, e.retryAfter)
+	}
+	return ErrRateLimited.Error()
+}
+
+// RetryAfter returns how long to wait before requesting the leaderboard again, or 0 if unknown.
+func (e *RateLimitError) RetryAfter() time.Duration { return e.retryAfter }
+
+func (e *RateLimitError) Is(target error) bool { return target == ErrRateLimited }
+
+// parseRetryAfter parses the value of a Retry-After header, which holds either a number of seconds
+// or an HTTP date, into the time to wait from now.
+func parseRetryAfter(value string) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && time.Until(t) > 0 {
+		return time.Until(t).Round(time.Second)
+	}
+	return 0
+}
+
+// LeaderboardErrors holds the errors of the leaderboards that could not be fetched, by ID.
+type LeaderboardErrors map[int]error
+
+func (e LeaderboardErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("leaderboard %d: %v", id, e[id])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual errors, so errors.Is and errors.As match any of them.
+func (e LeaderboardErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -0,0 +1,67 @@
+package leaderboard
+
+import "time"
+
+// StarsEarnedSince returns a StarEvent for every star the members earned after since, ordered by
+// the time it was earned.
+func StarsEarnedSince(members []Member, since time.Time) []StarEvent {
+	return starsWhere(members, func(e StarEvent) bool { return e.Timestamp.After(since) })
+}
+
+// StarsOnDate returns a StarEvent for every star the members earned on the calendar date of date
+// in loc, whichever day's puzzle it was for, ordered by the time it was earned. A nil loc means US
+// Eastern time.
+func StarsOnDate(members []Member, date time.Time, loc *time.Location) []StarEvent {
+	if loc == nil {
+		loc = eastern
+	}
+	year, month, day := date.In(loc).Date()
+	return starsWhere(members, func(e StarEvent) bool {
+		y, m, d := e.Timestamp.In(loc).Date()
+		return y == year && m == month && d == day
+	})
+}
+
+// StarsEarnedToday returns how many stars the members earned so far on the current calendar date
+// in loc. A nil loc means US Eastern time.
+func StarsEarnedToday(members []Member, loc *time.Location) int {
+	if loc == nil {
+		loc = eastern
+	}
+	return len(StarsOnDate(members, time.Now().In(loc), loc))
+}
+
+// WeeklyDigest returns, per member ID, the stars earned in the seven calendar days in loc
+// starting on the date of weekStart, ordered by the time they were earned. Members who did not
+// earn a star in that week are absent. A nil loc means US Eastern time.
+func WeeklyDigest(members []Member, weekStart time.Time, loc *time.Location) map[string][]StarEvent {
+	if loc == nil {
+		loc = eastern
+	}
+	year, month, day := weekStart.In(loc).Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 7)
+
+	digest := make(map[string][]StarEvent)
+	for _, e := range starsWhere(members, func(e StarEvent) bool {
+		return !e.Timestamp.Before(start) && e.Timestamp.Before(end)
+	}) {
+		digest[e.MemberID] = append(digest[e.MemberID], e)
+	}
+	return digest
+}
+
+// starsWhere returns the stars of the members for which keep returns true, in chronological order.
+// The result is empty, not nil, if there are none.
+func starsWhere(members []Member, keep func(StarEvent) bool) []StarEvent {
+	events := []StarEvent{}
+	for _, m := range members {
+		for _, e := range m.starEvents() {
+			if keep(e) {
+				events = append(events, e)
+			}
+		}
+	}
+	sortStarEvents(events)
+	return events
+}
@@ -0,0 +1,44 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+)
+
+// lateStarMember returns a member who earned the star of day 4 part 1 at 23:30 US Eastern time on
+// December 4, which is already December 5 in UTC.
+func lateStarMember() Member {
+	ts := time.Date(2023, time.December, 4, 23, 30, 0, 0, eastern)
+	return Member{
+		ID:   "1",
+		Days: map[string]map[string]Level{"4": {"1": {Timestamp: JSONTime{Time: ts}}}},
+	}
+}
+
+func TestStarsOnDateUsesLocation(t *testing.T) {
+	members := []Member{lateStarMember()}
+	date := time.Date(2023, time.December, 5, 2, 0, 0, 0, time.UTC) // December 4 in US Eastern time
+	if got := StarsOnDate(members, date, eastern); len(got) != 1 {
+		t.Errorf("StarsOnDate(Dec 4 Eastern) returned %d stars, want 1", len(got))
+	}
+	if got := StarsOnDate(members, date, time.UTC); len(got) != 1 {
+		t.Errorf("StarsOnDate(Dec 5 UTC) returned %d stars, want 1", len(got))
+	}
+	if got := StarsOnDate(members, date.Add(-24*time.Hour), time.UTC); len(got) != 0 {
+		t.Errorf("StarsOnDate(Dec 4 UTC) returned %d stars, want 0", len(got))
+	}
+}
+
+func TestWeeklyDigestUsesLocation(t *testing.T) {
+	members := []Member{lateStarMember()}
+	weekStart := time.Date(2023, time.December, 5, 2, 0, 0, 0, time.UTC) // December 4 in US Eastern time
+	if got := WeeklyDigest(members, weekStart, eastern); len(got["1"]) != 1 {
+		t.Errorf("WeeklyDigest(from Dec 4 Eastern) = %v, want 1 star for member 1", got)
+	}
+	if got := WeeklyDigest(members, weekStart, time.UTC); len(got["1"]) != 1 {
+		t.Errorf("WeeklyDigest(from Dec 5 UTC) = %v, want 1 star for member 1", got)
+	}
+	if got := WeeklyDigest(members, weekStart.AddDate(0, 0, 1), time.UTC); len(got) != 0 {
+		t.Errorf("WeeklyDigest(from Dec 6 UTC) = %v, want no stars", got)
+	}
+}
@@ -0,0 +1,171 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteCSV writes the members to w as CSV, with a header row followed by one row per member in
+// the order given. The rank is the position in the slice.
+func WriteCSV(w io.Writer, members []Member) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"rank", "name", "local score", "global score", "stars", "last star time"}); err != nil {
+		return err
+	}
+	for i, m := range members {
+		record := []string{
+			strconv.Itoa(i + 1),
+			m.DisplayName(),
+			strconv.Itoa(m.LocalScore),
+			strconv.Itoa(m.GlobalScore),
+			strconv.Itoa(m.Stars),
+			formatTimestamp(m.LastStarTS),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown writes the members to w as a GitHub-flavored Markdown table with rank, name, stars
+// and local score columns, in the order given.
+func WriteMarkdown(w io.Writer, members []Member) error {
+	if _, err := io.WriteString(w, "| Rank | Name | Stars | Local score |\n| ---: | --- | ---: | ---: |\n"); err != nil {
+		return err
+	}
+	for i, m := range members {
+		name := strings.ReplaceAll(m.DisplayName(), "|", "\\|")
+		if _, err := fmt.Fprintf(w, "| %d | %s | %d | %d |\n", i+1, name, m.Stars, m.LocalScore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTable writes the members to w as a plain text table with aligned rank, name, stars and
+// local score columns, in the order given.
+func WriteTable(w io.Writer, members []Member) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tNAME\tSTARS\tLOCAL SCORE")
+	for i, m := range members {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\n", i+1, strings.ReplaceAll(m.DisplayName(), "\t", " "), m.Stars, m.LocalScore)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the members to w as a JSON array, in the order given.
+func WriteJSON(w io.Writer, members []Member) error {
+	if members == nil {
+		members = []Member{}
+	}
+	return json.NewEncoder(w).Encode(members)
+}
+
+// WriteRankedJSON writes the ranked members, as returned by AssignRanks, to w as a JSON array of
+// members with an additional rank field.
+func WriteRankedJSON(w io.Writer, ranked []RankedMember) error {
+	if ranked == nil {
+		ranked = []RankedMember{}
+	}
+	return json.NewEncoder(w).Encode(ranked)
+}
+
+var htmlTable = template.Must(template.New("table").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<table class="leaderboard">
+{{- with .Title}}
+<caption>{{.}}</caption>
+{{- end}}
+<thead><tr><th>Rank</th><th>Name</th><th>Stars</th><th>Local score</th></tr></thead>
+<tbody>
+{{- range $i, $m := .Members}}
+<tr><td>{{inc $i}}</td><td>{{$m.DisplayName}}</td><td>{{$m.Stars}}</td><td>{{$m.LocalScore}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+`))
+
+// WriteHTML writes the members to w as an HTML table with rank, name, stars and local score
+// columns, in the order given. Member names are escaped.
+func WriteHTML(w io.Writer, members []Member) error {
+	return WriteHTMLTitled(w, "", members)
+}
+
+// WriteHTMLTitled is like WriteHTML but adds title as the caption of the table.
+func WriteHTMLTitled(w io.Writer, title string, members []Member) error {
+	return htmlTable.Execute(w, struct {
+		Title   string
+		Members []Member
+	}{title, members})
+}
+
+// maxChatNameLength is the number of characters after which FormatChatMessage truncates names.
+const maxChatNameLength = 20
+
+// FormatChatMessage formats the first topN members, or all of them if topN is zero or negative,
+// as a compact standings list in a code block, ready to be posted to Slack or Discord. Names
+// longer than 20 characters are truncated.
+func FormatChatMessage(members []Member, topN int) string {
+	if topN > 0 {
+		members = TopN(members, topN)
+	}
+	var b strings.Builder
+	b.WriteString("```\n")
+	for i, m := range members {
+		name := []rune(m.DisplayName())
+		if len(name) > maxChatNameLength {
+			name = append(name[:maxChatNameLength-1], '…')
+		}
+		fmt.Fprintf(&b, "%3d. %-*s %5d ⭐%d\n", i+1, maxChatNameLength, string(name), m.LocalScore, m.Stars)
+	}
+	b.WriteString("```")
+	return b.String()
+}
+
+// formatTimestamp formats t as RFC3339 in UTC, or as an empty string if t is zero.
+func formatTimestamp(t JSONTime) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Standings returns the given private leaderboard sorted by local score and formatted as "table",
+// "csv", "markdown" or "json", using DefaultClient. See Client.Standings.
+func Standings(ctx context.Context, lbID int, cookie string, year int, format string) (string, error) {
+	return DefaultClient.Standings(ctx, lbID, cookie, year, format)
+}
+
+// Standings returns the given private leaderboard sorted by local score and formatted as "table"
+// (see WriteTable), "csv" (WriteCSV), "markdown" (WriteMarkdown) or "json" (WriteJSON).
+func (c *Client) Standings(ctx context.Context, lbID int, cookie string, year int, format string) (string, error) {
+	writers := map[string]func(io.Writer, []Member) error{
+		"table":    WriteTable,
+		"csv":      WriteCSV,
+		"markdown": WriteMarkdown,
+		"json":     WriteJSON,
+	}
+	write, ok := writers[format]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown standings format %q", ErrInvalidArgument, format)
+	}
+	members, err := c.GetMembers(ctx, lbID, cookie, year, SortByLocalScore)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := write(&sb, members); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,55 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteRankedJSON(t *testing.T) {
+	lb, err := ParseLeaderboardBytes(readFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranked := AssignRanks(lb.MemberSlice(NoSort), SortByLocalScore)
+	var buf bytes.Buffer
+	if err := WriteRankedJSON(&buf, ranked); err != nil {
+		t.Fatal(err)
+	}
+
+	var fields []map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("WriteRankedJSON wrote invalid JSON: %v", err)
+	}
+	var decoded []RankedMember
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(ranked) {
+		t.Fatalf("decoded %d ranked members, want %d", len(decoded), len(ranked))
+	}
+	for i, rm := range ranked {
+		if string(fields[i]["rank"]) == "" {
+			t.Errorf("member %q written without rank: %s", rm.ID, buf.Bytes())
+		}
+		got := decoded[i]
+		if got.Rank != rm.Rank || got.ID != rm.ID || got.LocalScore != rm.LocalScore || got.Extra != nil {
+			t.Errorf("decoded %+v, want %+v", got, rm)
+		}
+	}
+}
+
+func TestEnrichedMemberJSON(t *testing.T) {
+	e := EnrichedMember{Member: Member{ID: "1", Name: "a"}, LongestStreak: 3}
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got EnrichedMember
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "1" || got.LongestStreak != 3 || got.Extra != nil {
+		t.Errorf("decoded %+v from %s, want %+v", got, b, e)
+	}
+}
@@ -0,0 +1,107 @@
+package leaderboard
+
+import (
+	"strings"
+	"time"
+)
+
+// FilterMembers returns a new slice holding the members for which keep returns true, in their
+// original order. The result is empty, not nil, when nothing matches.
+func FilterMembers(members []Member, keep func(Member) bool) []Member {
+	kept := []Member{}
+	for _, m := range members {
+		if keep(m) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// TopN returns a copy of the first n members, or of all of them if there are fewer than n. It
+// returns an empty slice for n <= 0. Sort the members first to get e.g. the top 10 by local score.
+func TopN(members []Member, n int) []Member {
+	if n <= 0 {
+		return []Member{}
+	}
+	if n > len(members) {
+		n = len(members)
+	}
+	top := make([]Member, n)
+	copy(top, members)
+	return top
+}
+
+// FindMemberByID returns the member with the given ID, or the zero Member and false if there is none.
+func FindMemberByID(members []Member, id string) (Member, bool) {
+	return findMember(members, func(m Member) bool { return m.ID == id })
+}
+
+// MembersByID returns the members keyed by their ID, for quick lookups or to correlate members
+// across snapshots. If several members have the same ID, the last one is kept.
+func MembersByID(members []Member) map[string]Member {
+	byID := make(map[string]Member, len(members))
+	for _, m := range members {
+		byID[m.ID] = m
+	}
+	return byID
+}
+
+// FindMemberByName returns the first member whose name is exactly name, or the zero Member and
+// false if there is none.
+func FindMemberByName(members []Member, name string) (Member, bool) {
+	return findMember(members, func(m Member) bool { return m.Name == name })
+}
+
+// FindMemberByNameFold is like FindMemberByName but compares names case-insensitively.
+func FindMemberByNameFold(members []Member, name string) (Member, bool) {
+	return findMember(members, func(m Member) bool { return strings.EqualFold(m.Name, name) })
+}
+
+// InactiveMembers returns the members who have not earned any stars, in their original order.
+func InactiveMembers(members []Member) []Member {
+	return FilterMembers(members, func(m Member) bool { return m.Stars == 0 })
+}
+
+// StaleMembers returns the members who have not earned a star since the given time, including
+// those without any stars, in their original order.
+func StaleMembers(members []Member, since time.Time) []Member {
+	return FilterMembers(members, func(m Member) bool { return m.LastStarTS.IsZero() || m.LastStarTS.Before(since) })
+}
+
+// ExcludeMember returns a new slice holding the members except the one with the given ID, in their
+// original order.
+func ExcludeMember(members []Member, id string) []Member {
+	return FilterMembers(members, func(m Member) bool { return m.ID != id })
+}
+
+// ExcludeOwner is like ExcludeMember for the owner of lb, e.g. to rank participants without the
+// organizer.
+func ExcludeOwner(members []Member, lb *Leaderboard) []Member {
+	return ExcludeMember(members, lb.OwnerID)
+}
+
+// AnonymizeMembers returns copies of the members with their names replaced by the pseudonym mapped
+// to their ID, or "Anonymous #<id>" if their ID is not in mapping, for publishing standings
+// without revealing who is who.
+func AnonymizeMembers(members []Member, mapping map[string]string) []Member {
+	anonymized := make([]Member, len(members))
+	for i, m := range members {
+		if name, ok := mapping[m.ID]; ok {
+			m.Name = name
+		} else {
+			m.Name = "Anonymous #" + m.ID
+		}
+		anonymized[i] = m
+	}
+	return anonymized
+}
+
+// findMember returns the first member matching match.
+func findMember(members []Member, match func(Member) bool) (Member, bool) {
+	for _, m := range members {
+		if match(m) {
+			return m, true
+		}
+	}
+	return Member{}, false
+}
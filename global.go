@@ -0,0 +1,106 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GlobalEntry is a single entry of the global leaderboard of a day, which lists the first hundred
+// users worldwide to earn each star.
+type GlobalEntry struct {
+	Part      int // 2 for users getting both stars, 1 for users getting the first star
+	Rank      int
+	UserID    string
+	Name      string
+	Anonymous bool
+	Timestamp time.Time     // when the star was earned
+	Duration  time.Duration // time between the puzzle unlock and earning the star
+}
+
+var (
+	globalEntryRE = regexp.MustCompile(`(?s)<div class="leaderboard-entry"([^>]*)>(.*?)</div>`)
+	userIDRE      = regexp.MustCompile(`data-user-id="(\d+)"`)
+	positionRE    = regexp.MustCompile(`(?s)<span class="leaderboard-position">\s*(\d*)\)?\s*</span>`)
+	entryTimeRE   = regexp.MustCompile(`(?s)<span class="leaderboard-time">(.*?)</span>`)
+	anonRE        = regexp.MustCompile(`(?s)<span class="leaderboard-anon">(.*?)</span>`)
+	badgeRE       = regexp.MustCompile(`(?s)<span class="leaderboard-userphoto">.*?</span>|<a [^>]*class="(?:supporter|sponsor)-badge"[^>]*>.*?</a>`)
+	tagRE         = regexp.MustCompile(`<[^>]*>`)
+)
+
+// GetGlobalDayLeaderboard returns the global leaderboard of the given day using DefaultClient.
+// See Client.GetGlobalDayLeaderboard.
+func GetGlobalDayLeaderboard(ctx context.Context, year, day int, cookie string) ([]GlobalEntry, error) {
+	return DefaultClient.GetGlobalDayLeaderboard(ctx, year, day, cookie)
+}
+
+// GetGlobalDayLeaderboard returns the global leaderboard of the given day and year: the users to
+// get both stars first, followed by the users to get the first star first. The leaderboard is
+// public, so cookie may be empty. It is only available as an HTML page, which is scraped for its
+// entries.
+func (c *Client) GetGlobalDayLeaderboard(ctx context.Context, year, day int, cookie string) ([]GlobalEntry, error) {
+	c.init()
+	if err := validateYear(year); err != nil {
+		return nil, err
+	}
+	if err := validateDay(day); err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/%d/leaderboard/day/%d", c.baseURL, year, day)
+	resp, err := c.getPublic(ctx, url, cookie, map[string]string{"Accept": "text/html"})
+	if err != nil {
+		return nil, err
+	}
+	return parseGlobalDayLeaderboard(resp.String(), year, day), nil
+}
+
+// parseGlobalDayLeaderboard extracts the entries from the HTML of the global leaderboard page of
+// a day. The page lists the users to get both stars before those to get the first star; tied users
+// are shown without a position and share the rank of the entry before them.
+func parseGlobalDayLeaderboard(page string, year, day int) []GlobalEntry {
+	firstStar := strings.Index(page, `class="leaderboard-daydesc-first"`)
+	unlock := PuzzleUnlock(year, day)
+
+	entries := []GlobalEntry{}
+	rank, part := 0, 0
+	for _, loc := range globalEntryRE.FindAllStringSubmatchIndex(page, -1) {
+		attrs, inner := page[loc[2]:loc[3]], page[loc[4]:loc[5]]
+
+		entryPart := 2
+		if firstStar >= 0 && loc[0] > firstStar {
+			entryPart = 1
+		}
+		if entryPart != part {
+			part, rank = entryPart, 0
+		}
+		if m := positionRE.FindStringSubmatch(inner); m != nil && m[1] != "" {
+			rank, _ = strconv.Atoi(m[1])
+		}
+
+		e := GlobalEntry{Part: part, Rank: rank}
+		if m := userIDRE.FindStringSubmatch(attrs); m != nil {
+			e.UserID = m[1]
+		}
+		if m := entryTimeRE.FindStringSubmatch(inner); m != nil {
+			if t, err := time.ParseInLocation("Jan 02 15:04:05", strings.Join(strings.Fields(m[1]), " "), eastern); err == nil {
+				e.Timestamp = time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, eastern)
+				e.Duration = e.Timestamp.Sub(unlock)
+			}
+		}
+		if m := anonRE.FindStringSubmatch(inner); m != nil {
+			e.Anonymous = true
+			e.Name = html.UnescapeString(strings.TrimSpace(m[1]))
+		} else {
+			name := positionRE.ReplaceAllString(inner, "")
+			name = entryTimeRE.ReplaceAllString(name, "")
+			name = badgeRE.ReplaceAllString(name, "")
+			e.Name = html.UnescapeString(strings.TrimSpace(tagRE.ReplaceAllString(name, "")))
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,64 @@
+package leaderboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseGlobalDayLeaderboard(t *testing.T) {
+	page, err := os.ReadFile("testdata/global_day.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GlobalEntry{
+		{Part: 2, Rank: 1, UserID: "111", Name: "Alice & Co", Duration: 2*time.Minute + 5*time.Second},
+		{Part: 2, Rank: 2, UserID: "222", Name: "Bob", Duration: 2*time.Minute + 30*time.Second},
+		{Part: 2, Rank: 2, UserID: "333", Name: "(anonymous user #333)", Anonymous: true, Duration: 2*time.Minute + 30*time.Second},
+		{Part: 2, Rank: 4, UserID: "444", Name: "Dave", Duration: 3 * time.Minute},
+		{Part: 1, Rank: 1, UserID: "222", Name: "Bob", Duration: 50 * time.Second},
+		{Part: 1, Rank: 2, UserID: "333", Name: "(anonymous user #333)", Anonymous: true, Duration: time.Minute + time.Second},
+		{Part: 1, Rank: 2, UserID: "111", Name: "Alice & Co", Duration: time.Minute + time.Second},
+	}
+	unlock := PuzzleUnlock(2023, 1)
+	for i := range want {
+		want[i].Timestamp = unlock.Add(want[i].Duration)
+	}
+
+	got := parseGlobalDayLeaderboard(string(page), 2023, 1)
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if g, w := got[i], want[i]; g.Part != w.Part || g.Rank != w.Rank || g.UserID != w.UserID || g.Name != w.Name ||
+			g.Anonymous != w.Anonymous || g.Duration != w.Duration || !g.Timestamp.Equal(w.Timestamp) {
+			t.Errorf("entry %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestGetGlobalDayLeaderboardWithoutCookie(t *testing.T) {
+	page, err := os.ReadFile("testdata/global_day.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "" {
+			t.Errorf("request sent Cookie header %q", r.Header.Get("Cookie"))
+		}
+		w.Write(page)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	entries, err := c.GetGlobalDayLeaderboard(context.Background(), 2023, 1, "")
+	if err != nil {
+		t.Fatalf("GetGlobalDayLeaderboard without cookie: %v", err)
+	}
+	if len(entries) != 7 {
+		t.Errorf("got %d entries, want 7", len(entries))
+	}
+}
@@ -7,23 +7,36 @@
 package leaderboard
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
-
-	resty "gopkg.in/resty.v1"
 )
 
+// LeaderboardSort selects the order in which Members are returned. Every order falls back to the
+// member ID for members that tie, so sorting the same Members always gives the same result.
 type LeaderboardSort int
 
 const (
-	NoSort = iota
-	SortByLocalScore
-	SortByGlobalScore
-	SortByStars
+	NoSort               = iota // by ID
+	SortByLocalScore            // highest local score first
+	SortByGlobalScore           // highest global score first
+	SortByStars                 // most stars first
+	SortByName                  // alphabetically by name, anonymous members last
+	SortByLastStar              // most recent star first, members without stars last
+	SortByLocalScoreAsc         // lowest local score first
+	SortByGlobalScoreAsc        // lowest global score first
+	SortByStarsAsc              // fewest stars first
+	SortByLastStarAsc           // least recent star first, members without stars last
 )
 const timeLayout = "2006-01-02T15:04:05-0700"
 
@@ -38,11 +51,34 @@ func (t *JSONTime) UnmarshalJSON(b []byte) (err error) {
 		t.Time = time.Time{}
 		return
 	}
+	// Timestamps are quoted or bare Unix seconds; fractional seconds are truncated.
 	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return err
+		}
+		i, err = int64(f), nil
+	}
 	t.Time = time.Unix(i, 0)
 	return
 }
 
+// IsZero reports whether t holds no time, which Advent of Code signals with either null or a
+// Unix timestamp of 0, e.g. for the last star of a member without any stars.
+func (t JSONTime) IsZero() bool {
+	return t.Time.IsZero() || t.Unix() == 0
+}
+
+// MarshalJSON encodes the time as a quoted Unix timestamp in seconds, or null for the zero time,
+// matching the format used by Advent of Code.
+func (t JSONTime) MarshalJSON() ([]byte, error) {
+	if t.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(strconv.FormatInt(t.Unix(), 10))), nil
+}
+
 // Define the Leaderboard JSON structure
 type Leaderboard struct {
 	OwnerID string            `json:"owner_id"`
@@ -50,6 +86,71 @@ type Leaderboard struct {
 	Members map[string]Member `json:"members"`
 }
 
+// leaderboardJSON is the JSON structure of a Leaderboard, which accepts the event either as a
+// string or as a number.
+type leaderboardJSON struct {
+	OwnerID string            `json:"owner_id"`
+	Event   json.RawMessage   `json:"event"`
+	Members map[string]Member `json:"members"`
+}
+
+func (j leaderboardJSON) leaderboard() (Leaderboard, error) {
+	lb := Leaderboard{OwnerID: j.OwnerID, Members: j.Members}
+	event := strings.TrimSpace(string(j.Event))
+	switch {
+	case event == "" || event == "null":
+	case strings.HasPrefix(event, `"`):
+		if err := json.Unmarshal(j.Event, &lb.Event); err != nil {
+			return lb, err
+		}
+	default:
+		if _, err := strconv.ParseFloat(event, 64); err != nil {
+			return lb, fmt.Errorf("event %s is neither a string nor a number", event)
+		}
+		lb.Event = event
+	}
+	return lb, nil
+}
+
+func (lb *Leaderboard) UnmarshalJSON(b []byte) error {
+	var j leaderboardJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	parsed, err := j.leaderboard()
+	if err != nil {
+		return err
+	}
+	*lb = parsed
+	return nil
+}
+
+// Year returns the year of the event the leaderboard belongs to.
+func (lb *Leaderboard) Year() (int, error) {
+	year, err := strconv.Atoi(strings.TrimSpace(lb.Event))
+	if err != nil {
+		return 0, fmt.Errorf("leaderboard event %q is not a year", lb.Event)
+	}
+	return year, nil
+}
+
+// OwnerMember returns the Member who owns the leaderboard, and false if the owner is not among
+// its Members.
+func (lb *Leaderboard) OwnerMember() (Member, bool) {
+	m, ok := lb.Members[lb.OwnerID]
+	return m, ok
+}
+
+// Fingerprint returns a hex encoded SHA-256 hash of the leaderboard data, including every star
+// timestamp. It only changes when the data does, so comparing it to the previous fingerprint tells
+// whether anything changed since the last poll.
+func (lb *Leaderboard) Fingerprint() string {
+	// encoding/json writes map keys in sorted order, so the encoding is deterministic.
+	b, _ := json.Marshal(lb)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
 type Member struct {
 	ID          string                      `json:"id"`
 	Name        string                      `json:"name"`
@@ -58,12 +159,94 @@ type Member struct {
 	GlobalScore int                         `json:"global_score"`
 	LastStarTS  JSONTime                    `json:"last_star_ts"`
 	Days        map[string]map[string]Level `json:"completion_day_level"`
+	// Extra holds the fields of the member JSON that are not decoded into the other fields, so
+	// they are preserved when the Member is encoded again.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// memberJSON has the fields of Member without its JSON methods.
+type memberJSON Member
+
+// memberFields are the JSON fields decoded into the fields of Member.
+var memberFields = []string{"id", "name", "stars", "local_score", "global_score", "last_star_ts", "completion_day_level"}
+
+func (m *Member) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	var j memberJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	for _, f := range memberFields {
+		delete(fields, f)
+	}
+	if len(fields) > 0 {
+		j.Extra = fields
+	}
+	*m = Member(j)
+	return nil
+}
+
+func (m Member) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(memberJSON(m))
+	if err != nil || len(m.Extra) == 0 {
+		return b, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for f, v := range m.Extra {
+		if _, ok := fields[f]; !ok {
+			fields[f] = v
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// withoutExtra returns m without the given extra fields, e.g. those a type embedding Member encodes
+// itself.
+func withoutExtra(m Member, fields ...string) Member {
+	if len(m.Extra) == 0 {
+		return m
+	}
+	extra := make(map[string]json.RawMessage, len(m.Extra))
+	for f, v := range m.Extra {
+		extra[f] = v
+	}
+	for _, f := range fields {
+		delete(extra, f)
+	}
+	m.Extra = nil
+	if len(extra) > 0 {
+		m.Extra = extra
+	}
+	return m
+}
+
+// joinJSONObjects returns the JSON object holding the fields of the JSON objects a and b, in order.
+// Types embedding Member use it to add their own fields to the encoding of the Member.
+func joinJSONObjects(a, b []byte) []byte {
+	a, b = bytes.TrimSpace(a), bytes.TrimSpace(b)
+	if len(bytes.TrimSpace(a[1:len(a)-1])) == 0 {
+		return b
+	}
+	if len(bytes.TrimSpace(b[1:len(b)-1])) == 0 {
+		return a
+	}
+	joined := append(append([]byte(nil), a[:len(a)-1]...), ',')
+	return append(joined, b[1:]...)
 }
 
 type Level struct {
 	Timestamp JSONTime `json:"get_star_ts"`
+	StarIndex int64    `json:"star_index"` // position of the star among all stars earned in the event
 }
 
+// The score and star sorts are used reversed, so they compare IDs backwards to end up with the
+// lowest ID first among ties.
 type membersSortedByLocalScore []Member
 
 func (m membersSortedByLocalScore) Len() int      { return len(m) }
@@ -75,7 +258,10 @@ func (m membersSortedByLocalScore) Less(i, j int) bool {
 	if m[i].LocalScore > m[j].LocalScore {
 		return false
 	}
-	return m[i].Stars < m[j].Stars
+	if m[i].Stars != m[j].Stars {
+		return m[i].Stars < m[j].Stars
+	}
+	return idLess(m[j].ID, m[i].ID)
 }
 
 type membersSortedByGlobalScore []Member
@@ -89,7 +275,10 @@ func (m membersSortedByGlobalScore) Less(i, j int) bool {
 	if m[i].GlobalScore > m[j].GlobalScore {
 		return false
 	}
-	return m[i].LocalScore < m[j].LocalScore
+	if m[i].LocalScore != m[j].LocalScore {
+		return m[i].LocalScore < m[j].LocalScore
+	}
+	return idLess(m[j].ID, m[i].ID)
 }
 
 type membersSortedByStars []Member
@@ -103,54 +292,331 @@ func (m membersSortedByStars) Less(i, j int) bool {
 	if m[i].Stars > m[j].Stars {
 		return false
 	}
-	return m[i].LocalScore < m[j].LocalScore
+	if m[i].LocalScore != m[j].LocalScore {
+		return m[i].LocalScore < m[j].LocalScore
+	}
+	return idLess(m[j].ID, m[i].ID)
+}
+
+// membersSortedByName sorts alphabetically by name, ignoring case, with anonymous members last.
+type membersSortedByName []Member
+
+func (m membersSortedByName) Len() int      { return len(m) }
+func (m membersSortedByName) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m membersSortedByName) Less(i, j int) bool {
+	if (m[i].Name == "") != (m[j].Name == "") {
+		return m[j].Name == ""
+	}
+	a, b := strings.ToLower(m[i].Name), strings.ToLower(m[j].Name)
+	if a != b {
+		return a < b
+	}
+	return idLess(m[i].ID, m[j].ID)
+}
+
+// membersSortedByLastStar sorts by most recent star first, with members without stars last.
+type membersSortedByLastStar []Member
+
+func (m membersSortedByLastStar) Len() int      { return len(m) }
+func (m membersSortedByLastStar) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m membersSortedByLastStar) Less(i, j int) bool {
+	a, b := m[i].LastStarTS, m[j].LastStarTS
+	if a.IsZero() != b.IsZero() {
+		return b.IsZero()
+	}
+	if !a.Equal(b.Time) {
+		return a.After(b.Time)
+	}
+	return idLess(m[i].ID, m[j].ID)
+}
+
+// membersSortedAscending sorts by the keys returned by key, lowest first, and then by ID. It provides
+// the ascending counterparts of the score and star sorts, using the same keys as they do.
+type membersSortedAscending struct {
+	members []Member
+	key     func(Member) [2]int
+}
+
+func (m membersSortedAscending) Len() int { return len(m.members) }
+func (m membersSortedAscending) Swap(i, j int) {
+	m.members[i], m.members[j] = m.members[j], m.members[i]
+}
+func (m membersSortedAscending) Less(i, j int) bool {
+	a, b := m.key(m.members[i]), m.key(m.members[j])
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	if a[1] != b[1] {
+		return a[1] < b[1]
+	}
+	return idLess(m.members[i].ID, m.members[j].ID)
+}
+
+func localScoreKey(m Member) [2]int  { return [2]int{m.LocalScore, m.Stars} }
+func globalScoreKey(m Member) [2]int { return [2]int{m.GlobalScore, m.LocalScore} }
+func starsKey(m Member) [2]int       { return [2]int{m.Stars, m.LocalScore} }
+
+// membersSortedByLastStarAsc sorts by least recent star first, with members without stars last.
+type membersSortedByLastStarAsc []Member
+
+func (m membersSortedByLastStarAsc) Len() int      { return len(m) }
+func (m membersSortedByLastStarAsc) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m membersSortedByLastStarAsc) Less(i, j int) bool {
+	a, b := m[i].LastStarTS, m[j].LastStarTS
+	if a.IsZero() != b.IsZero() {
+		return b.IsZero()
+	}
+	if !a.Equal(b.Time) {
+		return a.Before(b.Time)
+	}
+	return idLess(m[i].ID, m[j].ID)
+}
+
+// idLess orders member IDs numerically, falling back to a plain string comparison for IDs of
+// equal length.
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// SortMembers sorts members in place using less, which reports whether a should come before b.
+// Use it for orderings not covered by the LeaderboardSort modes.
+func SortMembers(members []Member, less func(a, b Member) bool) {
+	sort.Slice(members, func(i, j int) bool { return less(members[i], members[j]) })
+}
+
+// SortMembersStable is like SortMembers but keeps equal members in their original order.
+func SortMembersStable(members []Member, less func(a, b Member) bool) {
+	sort.SliceStable(members, func(i, j int) bool { return less(members[i], members[j]) })
 }
 
 func JSONToNormalTime(jt JSONTime) (time.Time, error) {
 	t, err := time.Parse(time.RFC3339, jt.Format(time.RFC3339))
 	if err != nil {
-		return time.Now(), errors.New("could not convert JSON time")
+		return time.Time{}, errors.New("could not convert JSON time")
 	}
 	return t, nil
 }
 
 // GetMembers returns a slice of private leaderboard Members sorted by a sorting function
-// (SortByLocalScore, SortByGlobalScore or SortByStars) given the private leaderboard ID, a session
-// cookie and the year of the Advent of Code challenge.
+// (SortByLocalScore, SortByGlobalScore, SortByStars, ...) given the private leaderboard ID, a
+// session cookie and the year of the Advent of Code challenge.
 func GetMembers(lbID int, cookie string, year int, sorted LeaderboardSort) ([]Member, error) {
-	resp, err := resty.R().
-		SetHeader("Accept", "application/json").
-		SetHeader("Cookie", fmt.Sprintf("session=%s", cookie)).
-		SetResult(Leaderboard{}).
-		Get(fmt.Sprintf("https://adventofcode.com/%d/leaderboard/private/view/%d.json", year, lbID))
+	return GetMembersContext(context.Background(), lbID, cookie, year, sorted)
+}
+
+// GetMembersContext is like GetMembers but performs the request with the given context, so it can
+// be cancelled or bounded by a deadline. If the context ends first, ctx.Err() is returned.
+func GetMembersContext(ctx context.Context, lbID int, cookie string, year int, sorted LeaderboardSort) ([]Member, error) {
+	return DefaultClient.GetMembers(ctx, lbID, cookie, year, sorted)
+}
+
+// GetMultipleLeaderboards fetches several private leaderboards concurrently using DefaultClient.
+// See Client.GetMultipleLeaderboards.
+func GetMultipleLeaderboards(ctx context.Context, ids []int, cookie string, year int, sorted LeaderboardSort) (map[int][]Member, error) {
+	return DefaultClient.GetMultipleLeaderboards(ctx, ids, cookie, year, sorted)
+}
+
+// DefaultMaxMembers is the maximum number of members ParseLeaderboard and a Client accept in a
+// leaderboard, unless configured otherwise. Advent of Code limits private leaderboards to 200.
+const DefaultMaxMembers = 10000
+
+// Parser decodes JSON formatted private leaderboards with configurable checks. The zero value
+// behaves like ParseLeaderboard without a limit on the number of members.
+type Parser struct {
+	// Strict makes the Parser fail on JSON that does not have the expected shape, see
+	// ParseLeaderboardStrict.
+	Strict bool
+	// MaxMembers makes the Parser fail as soon as it encounters more members than this, before
+	// decoding them, to guard against corrupt responses or untrusted mirrors. A zero or negative
+	// value disables the limit.
+	MaxMembers int
+}
+
+// ParseLeaderboard decodes a JSON formatted private leaderboard, as served by Advent of Code, from r.
+// It fails for leaderboards with more than DefaultMaxMembers members.
+func ParseLeaderboard(r io.Reader) (*Leaderboard, error) {
+	return Parser{MaxMembers: DefaultMaxMembers}.Parse(r)
+}
+
+// ParseLeaderboardBytes decodes a JSON formatted private leaderboard from b.
+func ParseLeaderboardBytes(b []byte) (*Leaderboard, error) {
+	return ParseLeaderboard(bytes.NewReader(b))
+}
+
+// ParseLeaderboardStrict is like ParseLeaderboard but fails on JSON that does not have the expected
+// shape, instead of silently leaving fields empty: unknown fields, mistyped values, trailing data
+// and a missing event or members object are all errors.
+func ParseLeaderboardStrict(r io.Reader) (*Leaderboard, error) {
+	return Parser{Strict: true, MaxMembers: DefaultMaxMembers}.Parse(r)
+}
+
+// Parse decodes a JSON formatted private leaderboard from r.
+func (p Parser) Parse(r io.Reader) (*Leaderboard, error) {
+	dec := json.NewDecoder(r)
+	if p.Strict {
+		dec.DisallowUnknownFields()
+	}
+	j, err := p.decode(dec)
 	if err != nil {
-		return nil, err
+		return nil, p.invalid(err)
 	}
-	switch {
-	case resp.StatusCode() == 500:
-		return nil, errors.New("Advent of Code server error, wrong cookie perhaps?")
-	case resp.StatusCode() != 200:
-		return nil, fmt.Errorf("error connecting to Advent of Code, HTTP code %d", resp.StatusCode())
+	lb, err := j.leaderboard()
+	if err != nil {
+		return nil, p.invalid(err)
+	}
+	if !p.Strict {
+		return &lb, nil
 	}
 
-	lb := resp.Result().(*Leaderboard)
-	var members []Member
+	if dec.More() {
+		return nil, errors.New("invalid leaderboard JSON: unexpected data after leaderboard")
+	}
+	if lb.Event == "" {
+		return nil, errors.New("invalid leaderboard JSON: missing event")
+	}
+	if lb.Members == nil {
+		return nil, errors.New("invalid leaderboard JSON: missing members")
+	}
+	for key, m := range lb.Members {
+		if m.ID != key {
+			return nil, fmt.Errorf("invalid leaderboard JSON: member %q has ID %q", key, m.ID)
+		}
+	}
+	return &lb, nil
+}
 
-	for _, member := range lb.Members {
-		members = append(members, member)
+// invalid marks err as a shape error in strict mode, which is how ParseLeaderboardStrict reports
+// its errors.
+func (p Parser) invalid(err error) error {
+	if p.Strict && !errors.Is(err, ErrTooManyMembers) {
+		return fmt.Errorf("invalid leaderboard JSON: %w", err)
+	}
+	return err
+}
+
+// decode reads the leaderboard object from dec token by token, decoding the members one at a time
+// so the member limit is enforced before the excess members are decoded. Unknown fields are
+// skipped, or errors if dec disallows them. Like encoding/json, field names match case-insensitively.
+func (p Parser) decode(dec *json.Decoder) (leaderboardJSON, error) {
+	var j leaderboardJSON
+	if tok, err := dec.Token(); err != nil {
+		return j, err
+	} else if tok == nil {
+		return j, nil
+	} else if tok != json.Delim('{') {
+		return j, fmt.Errorf("leaderboard is %v, not an object", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return j, err
+		}
+		key, _ := tok.(string)
+		switch {
+		case strings.EqualFold(key, "owner_id"):
+			err = dec.Decode(&j.OwnerID)
+		case strings.EqualFold(key, "event"):
+			err = dec.Decode(&j.Event)
+		case strings.EqualFold(key, "members"):
+			err = p.decodeMembers(dec, &j)
+		case p.Strict:
+			err = fmt.Errorf("json: unknown field %q", key)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return j, err
+		}
+	}
+	_, err := dec.Token()
+	return j, err
+}
+
+// decodeMembers decodes the members object from dec into j.Members, failing as soon as it holds
+// more members than the Parser accepts. Strictly, members are decoded without Member's JSON methods,
+// so dec checks their fields for unknown ones as well.
+func (p Parser) decodeMembers(dec *json.Decoder, j *leaderboardJSON) error {
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok == nil {
+		return nil
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("members is %v, not an object", tok)
+	}
+	if j.Members == nil {
+		j.Members = make(map[string]Member)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if _, seen := j.Members[key]; !seen && p.MaxMembers > 0 && len(j.Members) >= p.MaxMembers {
+			return fmt.Errorf("%w: more than %d", ErrTooManyMembers, p.MaxMembers)
+		}
+		var m Member
+		if p.Strict {
+			err = dec.Decode((*memberJSON)(&m))
+		} else {
+			err = dec.Decode(&m)
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
+		j.Members[key] = m
 	}
+	_, err := dec.Token()
+	return err
+}
+
+// MembersFromLeaderboard returns the Members of lb as a slice sorted by a sorting function
+// (SortByLocalScore, SortByGlobalScore, SortByStars, ...), or by ID for NoSort. Members that tie
+// on the sort key are ordered by ID, so the result is deterministic even though lb stores its
+// Members in a map.
+func MembersFromLeaderboard(lb *Leaderboard, sorted LeaderboardSort) []Member {
+	return lb.MemberSlice(sorted)
+}
+
+// MemberSlice returns the Members of the leaderboard as a slice, sorted like MembersFromLeaderboard.
+// The slice is empty, not nil, for a leaderboard without Members.
+func (lb *Leaderboard) MemberSlice(sorted LeaderboardSort) []Member {
+	members := make([]Member, 0, len(lb.Members))
+	for _, member := range lb.Members {
+		members = append(members, member)
+	}
+	sortBy(members, sorted)
+	return members
+}
+
+// sortBy sorts members in place according to sorted, or by ID for NoSort.
+func sortBy(members []Member, sorted LeaderboardSort) {
+	// Start from ID order, so ties keep it through the stable sorts whatever the input order was.
+	sort.Slice(members, func(i, j int) bool { return idLess(members[i].ID, members[j].ID) })
 	switch sorted {
 	case SortByLocalScore:
-		sort.Sort(sort.Reverse(membersSortedByLocalScore(members)))
+		sort.Stable(sort.Reverse(membersSortedByLocalScore(members)))
 	case SortByGlobalScore:
-		sort.Sort(sort.Reverse(membersSortedByGlobalScore(members)))
+		sort.Stable(sort.Reverse(membersSortedByGlobalScore(members)))
 	case SortByStars:
-		sort.Sort(sort.Reverse(membersSortedByStars(members)))
+		sort.Stable(sort.Reverse(membersSortedByStars(members)))
+	case SortByName:
+		sort.Stable(membersSortedByName(members))
+	case SortByLastStar:
+		sort.Stable(membersSortedByLastStar(members))
+	case SortByLocalScoreAsc:
+		sort.Stable(membersSortedAscending{members, localScoreKey})
+	case SortByGlobalScoreAsc:
+		sort.Stable(membersSortedAscending{members, globalScoreKey})
+	case SortByStarsAsc:
+		sort.Stable(membersSortedAscending{members, starsKey})
+	case SortByLastStarAsc:
+		sort.Stable(membersSortedByLastStarAsc(members))
 	}
-	return members, nil
 }
 
 // CountTotalStars counts the total number of stars from the given slice of Members.
@@ -0,0 +1,130 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseLeaderboardStrict(t *testing.T) {
+	fixture := readFixture(t)
+	lb, err := ParseLeaderboardStrict(bytes.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("ParseLeaderboardStrict(fixture): %v", err)
+	}
+	if len(lb.Members) != 5 {
+		t.Errorf("got %d members, want 5", len(lb.Members))
+	}
+	if l := lb.Members["101"].Days["1"]["2"]; l.StarIndex != 1544 || l.Timestamp.Unix() != 1701407300 {
+		t.Errorf("day 1 part 2 of member 101 = %+v", l)
+	}
+
+	unknown := strings.Replace(string(fixture), `"star_index": 1201`, `"star_index": 1201, "unknown": 1`, 1)
+	if _, err := ParseLeaderboardStrict(strings.NewReader(unknown)); err == nil {
+		t.Error("ParseLeaderboardStrict accepted an unknown level field")
+	}
+}
+
+func TestParserMaxMembers(t *testing.T) {
+	fixture := readFixture(t)
+	for _, strict := range []bool{false, true} {
+		if _, err := (Parser{Strict: strict, MaxMembers: 4}).Parse(bytes.NewReader(fixture)); !errors.Is(err, ErrTooManyMembers) {
+			t.Errorf("Parser{Strict: %v, MaxMembers: 4} error = %v, want ErrTooManyMembers", strict, err)
+		}
+		if _, err := (Parser{Strict: strict, MaxMembers: 5}).Parse(bytes.NewReader(fixture)); err != nil {
+			t.Errorf("Parser{Strict: %v, MaxMembers: 5} error = %v", strict, err)
+		}
+	}
+}
+
+// endlessMembers is a leaderboard whose members object never ends.
+type endlessMembers struct {
+	next int
+	buf  []byte
+}
+
+func (r *endlessMembers) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.next == 0 {
+			r.buf = []byte(`{"event":"2023","members":{`)
+		} else {
+			r.buf = []byte(fmt.Sprintf(`"%d":{"id":"%d"},`, r.next, r.next))
+		}
+		r.next++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func TestParseLeaderboardStopsAtMaxMembers(t *testing.T) {
+	r := &endlessMembers{}
+	if _, err := ParseLeaderboard(r); !errors.Is(err, ErrTooManyMembers) {
+		t.Fatalf("ParseLeaderboard error = %v, want ErrTooManyMembers", err)
+	}
+	if r.next > DefaultMaxMembers+100 {
+		t.Errorf("ParseLeaderboard read %d members before failing", r.next-1)
+	}
+}
+
+func TestJSONTimeUnmarshal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		zero bool
+	}{
+		{in: `"1607000000"`, want: 1607000000},
+		{in: `1607000000`, want: 1607000000},
+		{in: `1607000000.75`, want: 1607000000},
+		{in: `"0"`, zero: true},
+		{in: `null`, zero: true},
+	}
+	for _, tt := range tests {
+		var jt JSONTime
+		if err := json.Unmarshal([]byte(tt.in), &jt); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if jt.IsZero() != tt.zero {
+			t.Errorf("Unmarshal(%s).IsZero() = %v, want %v", tt.in, jt.IsZero(), tt.zero)
+		}
+		if !tt.zero && jt.Unix() != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, jt.Unix(), tt.want)
+		}
+	}
+
+	for _, in := range []string{`"yesterday"`, `"NaN"`, `"Inf"`, `"-Inf"`, `"1e19"`, `-1e19`} {
+		var jt JSONTime
+		if err := json.Unmarshal([]byte(in), &jt); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", in, jt)
+		}
+	}
+}
+
+func TestAssignRanksNoSortIsByID(t *testing.T) {
+	members := []Member{{ID: "9"}, {ID: "10"}, {ID: "2"}}
+	ranked := AssignRanks(members, NoSort)
+	var got []string
+	for _, m := range ranked {
+		got = append(got, fmt.Sprintf("%d:%s", m.Rank, m.ID))
+	}
+	if want := "[1:2 2:9 3:10]"; fmt.Sprint(got) != want {
+		t.Errorf("AssignRanks(%v, NoSort) = %v, want %s", members, got, want)
+	}
+	if members[0].ID != "9" {
+		t.Errorf("AssignRanks reordered its input to %v", members)
+	}
+}
+
+func TestEmbeddingMemberString(t *testing.T) {
+	m := Member{ID: "1", Name: "A"}
+	if got, want := fmt.Sprint(RankedMember{Rank: 7, Member: m}), "#7 "+m.String(); got != want {
+		t.Errorf("RankedMember prints as %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprint(EnrichedMember{Member: m, LongestStreak: 5, DaysFullyCompleted: 4}), m.String()+" streak=5 full=4"; got != want {
+		t.Errorf("EnrichedMember prints as %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,264 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StarEvent describes a single star earned by a Member.
+type StarEvent struct {
+	MemberID  string
+	Name      string
+	Day       int
+	Part      int
+	Timestamp time.Time
+}
+
+// level returns the completion Level of the given day and part, and whether the Member completed it.
+func (m Member) level(day, part int) (Level, bool) {
+	l, ok := m.Days[strconv.Itoa(day)][strconv.Itoa(part)]
+	return l, ok
+}
+
+// StarsOnDay returns the number of stars (0, 1 or 2) the Member earned on the given day.
+func (m Member) StarsOnDay(day int) int {
+	stars := 0
+	for part := 1; part <= 2; part++ {
+		if m.CompletedPart(day, part) {
+			stars++
+		}
+	}
+	return stars
+}
+
+// CompletedPart reports whether the Member earned the star for the given part (1 or 2) of a day.
+func (m Member) CompletedPart(day, part int) bool {
+	_, ok := m.level(day, part)
+	return ok
+}
+
+// CompletedDay reports whether the Member earned both stars of the given day.
+func (m Member) CompletedDay(day int) bool {
+	return m.CompletedPart(day, 1) && m.CompletedPart(day, 2)
+}
+
+// PartDelta returns the time between earning the first and the second star of the given day, and
+// false if the Member did not earn both. Inconsistent timestamps, where part 2 appears to be
+// completed before part 1, result in a zero duration.
+func (m Member) PartDelta(day int) (time.Duration, bool) {
+	first, ok1 := m.level(day, 1)
+	second, ok2 := m.level(day, 2)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	d := second.Timestamp.Sub(first.Timestamp.Time)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// DaysCompleted returns the number of days on which the Member earned at least one star.
+func (m Member) DaysCompleted() int {
+	days := 0
+	for _, levels := range m.Days {
+		if len(levels) > 0 {
+			days++
+		}
+	}
+	return days
+}
+
+// DaysFullyCompleted returns the number of days on which the Member earned both stars.
+func (m Member) DaysFullyCompleted() int {
+	days := 0
+	for _, levels := range m.Days {
+		_, ok1 := levels["1"]
+		_, ok2 := levels["2"]
+		if ok1 && ok2 {
+			days++
+		}
+	}
+	return days
+}
+
+// DisplayName returns the Member's name, or "(anonymous user #<id>)" like the Advent of Code site
+// shows for members who have not set a public name.
+func (m Member) DisplayName() string {
+	if m.Name == "" {
+		return "(anonymous user #" + m.ID + ")"
+	}
+	return m.Name
+}
+
+// String returns a compact summary of the Member for logging and debugging, such as
+// "Alice (id=123) ⭐12 local=340 global=0 last=2023-12-12", with the date of the last star in UTC.
+func (m Member) String() string {
+	last := "never"
+	if !m.LastStarTS.IsZero() {
+		last = m.LastStarTS.UTC().Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s (id=%s) ⭐%d local=%d global=%d last=%s",
+		m.DisplayName(), m.ID, m.Stars, m.LocalScore, m.GlobalScore, last)
+}
+
+// SolveDuration returns the time between the unlock of the given day's puzzle in year and the
+// Member earning the star for the given part, and false if the Member did not earn it.
+func (m Member) SolveDuration(day, part, year int) (time.Duration, bool) {
+	return m.SolveDurationIn(day, part, year, eastern)
+}
+
+// SolveDurationIn is like SolveDuration but measures from midnight in loc, see PuzzleUnlockIn.
+func (m Member) SolveDurationIn(day, part, year int, loc *time.Location) (time.Duration, bool) {
+	l, ok := m.level(day, part)
+	if !ok {
+		return 0, false
+	}
+	return l.Timestamp.Sub(PuzzleUnlockIn(year, day, loc)), true
+}
+
+// LongestStreak returns the length of the Member's longest run of consecutive days with at least
+// one star.
+func (m Member) LongestStreak() int {
+	longest, current, prev := 0, 0, 0
+	for _, day := range m.activeDays() {
+		if day == prev+1 {
+			current++
+		} else {
+			current = 1
+		}
+		if current > longest {
+			longest = current
+		}
+		prev = day
+	}
+	return longest
+}
+
+// CurrentStreak returns the number of consecutive days with at least one star, counting back from
+// day asOf. It is 0 if the Member earned no star on day asOf.
+func (m Member) CurrentStreak(asOf int) int {
+	streak := 0
+	for day := asOf; day > 0 && m.StarsOnDay(day) > 0; day-- {
+		streak++
+	}
+	return streak
+}
+
+// Timeline returns the stars the Member earned in chronological order. Stars without a timestamp
+// are omitted.
+func (m Member) Timeline() []StarEvent {
+	events := []StarEvent{}
+	for _, e := range m.starEvents() {
+		if !(JSONTime{Time: e.Timestamp}).IsZero() {
+			events = append(events, e)
+		}
+	}
+	sortStarEvents(events)
+	return events
+}
+
+// ActiveSpan returns the time between the first and the last star the Member earned, and false if
+// the Member has not earned any stars with a timestamp.
+func (m Member) ActiveSpan() (time.Duration, bool) {
+	timeline := m.Timeline()
+	if len(timeline) == 0 {
+		return 0, false
+	}
+	return timeline[len(timeline)-1].Timestamp.Sub(timeline[0].Timestamp), true
+}
+
+// LateCompletions returns, in ascending order, the days on which the Member earned their first
+// star more than thresholdDays days after the puzzle of that day in year unlocked. The result is
+// empty, not nil, when there are none.
+func (m Member) LateCompletions(year, thresholdDays int) []int {
+	return m.LateCompletionsIn(year, thresholdDays, eastern)
+}
+
+// LateCompletionsIn is like LateCompletions but measures from midnight in loc, see PuzzleUnlockIn.
+func (m Member) LateCompletionsIn(year, thresholdDays int, loc *time.Location) []int {
+	threshold := time.Duration(thresholdDays) * 24 * time.Hour
+	late := []int{}
+	for _, day := range m.activeDays() {
+		var first time.Time
+		for _, l := range m.Days[strconv.Itoa(day)] {
+			if !l.Timestamp.IsZero() && (first.IsZero() || l.Timestamp.Before(first)) {
+				first = l.Timestamp.Time
+			}
+		}
+		if !first.IsZero() && first.Sub(PuzzleUnlockIn(year, day, loc)) > threshold {
+			late = append(late, day)
+		}
+	}
+	return late
+}
+
+// StarsPerActiveDay returns the Member's stars divided by the number of distinct calendar days, in
+// US Eastern time, on which the Member earned at least one star. It returns 0 for a Member
+// without stars.
+func (m Member) StarsPerActiveDay() float64 {
+	dates := make(map[string]bool)
+	for _, e := range m.Timeline() {
+		dates[e.Timestamp.In(eastern).Format("2006-01-02")] = true
+	}
+	if m.Stars == 0 || len(dates) == 0 {
+		return 0
+	}
+	return float64(m.Stars) / float64(len(dates))
+}
+
+// starEvents returns a StarEvent for every star the Member earned, in no particular order.
+func (m Member) starEvents() []StarEvent {
+	var events []StarEvent
+	for d, levels := range m.Days {
+		day, err := strconv.Atoi(d)
+		if err != nil {
+			continue
+		}
+		for p, l := range levels {
+			part, err := strconv.Atoi(p)
+			if err != nil {
+				continue
+			}
+			events = append(events, StarEvent{
+				MemberID:  m.ID,
+				Name:      m.Name,
+				Day:       day,
+				Part:      part,
+				Timestamp: l.Timestamp.Time,
+			})
+		}
+	}
+	return events
+}
+
+// sortStarEvents sorts events chronologically, ordering simultaneous stars by member ID, day and
+// part.
+func sortStarEvents(events []StarEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		switch {
+		case !a.Timestamp.Equal(b.Timestamp):
+			return a.Timestamp.Before(b.Timestamp)
+		case a.MemberID != b.MemberID:
+			return idLess(a.MemberID, b.MemberID)
+		case a.Day != b.Day:
+			return a.Day < b.Day
+		}
+		return a.Part < b.Part
+	})
+}
+
+// activeDays returns the days on which the Member earned at least one star, in ascending order.
+func (m Member) activeDays() []int {
+	var days []int
+	for d, levels := range m.Days {
+		if day, err := strconv.Atoi(d); err == nil && len(levels) > 0 {
+			days = append(days, day)
+		}
+	}
+	sort.Ints(days)
+	return days
+}
@@ -0,0 +1,100 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PartStats are the personal statistics of a single part of a day's puzzle.
+type PartStats struct {
+	Completed bool
+	Duration  time.Duration // time between the puzzle unlock and earning the star, zero if Over24h
+	Over24h   bool          // the star was earned more than 24 hours after the puzzle unlocked
+	Rank      int           // position among all users to earn the star
+	Score     int           // global score earned, only non-zero for the first hundred users
+}
+
+// DayStats are the personal statistics of a day, as listed on the personal stats page.
+type DayStats struct {
+	Day   int
+	Part1 PartStats
+	Part2 PartStats
+}
+
+var (
+	preRE      = regexp.MustCompile(`(?s)<pre>(.*?)</pre>`)
+	dayStatsRE = regexp.MustCompile(`^\s*(\d+)\s+(\S+)\s+(\S+)\s+(\S+)(?:\s+(\S+)\s+(\S+)\s+(\S+))?\s*$`)
+)
+
+// GetMemberStats returns the personal statistics of the given year using DefaultClient. See
+// Client.GetMemberStats.
+func GetMemberStats(ctx context.Context, year int, cookie string) ([]DayStats, error) {
+	return DefaultClient.GetMemberStats(ctx, year, cookie)
+}
+
+// GetMemberStats returns the personal statistics of the given year for the user the session cookie
+// belongs to: per day on which the user earned a star, the time each part took and the position
+// among all users to solve it, latest day first. Advent of Code does not publish these
+// statistics for other users, so they are unavailable for the other members of a private
+// leaderboard. The statistics are only available as an HTML page, which is scraped for them.
+func (c *Client) GetMemberStats(ctx context.Context, year int, cookie string) ([]DayStats, error) {
+	c.init()
+	if err := validateYear(year); err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/%d/leaderboard/self", c.baseURL, year)
+	resp, err := c.get(ctx, url, cookie, map[string]string{"Accept": "text/html"})
+	if err != nil {
+		return nil, err
+	}
+	return parseMemberStats(resp.String()), nil
+}
+
+// parseMemberStats extracts the rows of the table on the personal stats page, which lists one day
+// per line with the time, rank and score of both parts, or dashes for a part not completed.
+func parseMemberStats(page string) []DayStats {
+	stats := []DayStats{}
+	m := preRE.FindStringSubmatch(page)
+	if m == nil {
+		return stats
+	}
+	table := html.UnescapeString(tagRE.ReplaceAllString(m[1], ""))
+	for _, line := range strings.Split(table, "\n") {
+		fields := dayStatsRE.FindStringSubmatch(line)
+		if fields == nil {
+			continue
+		}
+		day, _ := strconv.Atoi(fields[1])
+		stats = append(stats, DayStats{
+			Day:   day,
+			Part1: parsePartStats(fields[2], fields[3], fields[4]),
+			Part2: parsePartStats(fields[5], fields[6], fields[7]),
+		})
+	}
+	return stats
+}
+
+// parsePartStats parses the time, rank and score columns of a part, such as "01:02:03", "1234"
+// and "0", or ">24h" for the time of a part that took more than a day.
+func parsePartStats(duration, rank, score string) PartStats {
+	if duration == "" || duration == "-" {
+		return PartStats{}
+	}
+	s := PartStats{Completed: true}
+	s.Rank, _ = strconv.Atoi(rank)
+	s.Score, _ = strconv.Atoi(score)
+	if duration == ">24h" {
+		s.Over24h = true
+		return s
+	}
+	var h, m, sec int
+	if _, err := fmt.Sscanf(duration, "%d:%d:%d", &h, &m, &sec); err == nil {
+		s.Duration = time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+	}
+	return s
+}
@@ -0,0 +1,26 @@
+package leaderboard
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseMemberStats(t *testing.T) {
+	page, err := os.ReadFile("testdata/personal_stats.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DayStats{
+		{Day: 6, Part1: PartStats{Completed: true, Duration: 14*time.Minute + 9*time.Second, Rank: 470}},
+		{Day: 3, Part1: PartStats{Completed: true, Over24h: true, Rank: 51234}, Part2: PartStats{Completed: true, Over24h: true, Rank: 48001}},
+		{Day: 2, Part1: PartStats{Completed: true, Duration: time.Hour + 2*time.Minute + 3*time.Second, Rank: 7890},
+			Part2: PartStats{Completed: true, Duration: time.Hour + 10*time.Minute, Rank: 6543}},
+		{Day: 1, Part1: PartStats{Completed: true, Duration: 90 * time.Second, Rank: 42, Score: 59},
+			Part2: PartStats{Completed: true, Duration: 3*time.Minute + 5*time.Second, Rank: 17, Score: 84}},
+	}
+	if got := parseMemberStats(string(page)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMemberStats =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+)
+
+// FirstYear is the year of the first Advent of Code event.
+const FirstYear = 2015
+
+// eastern is the time zone in which Advent of Code puzzles unlock. Without a time zone database
+// it falls back to EST, which is what New York observes throughout December.
+var eastern = loadEastern()
+
+func loadEastern() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
+// PuzzleUnlock returns the time at which the puzzle of the given day and year unlocks: midnight US
+// Eastern time.
+func PuzzleUnlock(year, day int) time.Time {
+	return PuzzleUnlockIn(year, day, eastern)
+}
+
+// PuzzleUnlockIn is like PuzzleUnlock but returns midnight in loc instead, for side competitions
+// that use another reference time zone. A nil loc means US Eastern time.
+func PuzzleUnlockIn(year, day int, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = eastern
+	}
+	return time.Date(year, time.December, day, 0, 0, 0, 0, loc)
+}
+
+// EventProgress returns how many puzzles of the event in the given year have unlocked at now, and
+// how many puzzles the event has in total, e.g. for a "Day 14 of 25" banner. The days are counted
+// in US Eastern time, so the result does not depend on the time zone of now.
+func EventProgress(year int, now time.Time) (daysElapsed, daysTotal int) {
+	const days = 25
+	switch now = now.In(eastern); {
+	case now.Before(PuzzleUnlock(year, 1)):
+		return 0, days
+	case !now.Before(PuzzleUnlock(year, days)):
+		return days, days
+	default:
+		return now.Day(), days
+	}
+}
+
+// validateYear returns an ErrInvalidArgument error unless an event was or is held in year.
+func validateYear(year int) error {
+	if year < FirstYear || year > time.Now().In(eastern).Year() {
+		return fmt.Errorf("%w: no Advent of Code event in year %d", ErrInvalidArgument, year)
+	}
+	return nil
+}
+
+// validateDay returns an ErrInvalidArgument error unless day is a day of the event.
+func validateDay(day int) error {
+	if day < 1 || day > 25 {
+		return fmt.Errorf("%w: day %d is not between 1 and 25", ErrInvalidArgument, day)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RankedMember is a Member together with its rank on the leaderboard. It is encoded as JSON like
+// the Member, with an additional rank field.
+type RankedMember struct {
+	Rank int `json:"rank"`
+	Member
+}
+
+// String returns the String of the Member prefixed with its rank, such as "#7 Alice (id=123) ...".
+// It is needed because RankedMember would otherwise use the method of the embedded Member, leaving
+// out the rank.
+func (rm RankedMember) String() string {
+	return "#" + strconv.Itoa(rm.Rank) + " " + rm.Member.String()
+}
+
+// MarshalJSON is needed because RankedMember would otherwise use the method of the embedded Member,
+// leaving out the rank.
+func (rm RankedMember) MarshalJSON() ([]byte, error) {
+	member, err := withoutExtra(rm.Member, "rank").MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return joinJSONObjects([]byte(`{"rank":`+strconv.Itoa(rm.Rank)+`}`), member), nil
+}
+
+func (rm *RankedMember) UnmarshalJSON(b []byte) error {
+	var rank struct {
+		Rank int `json:"rank"`
+	}
+	if err := json.Unmarshal(b, &rank); err != nil {
+		return err
+	}
+	var m Member
+	if err := m.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	*rm = RankedMember{Rank: rank.Rank, Member: withoutExtra(m, "rank")}
+	return nil
+}
+
+// DailyRanking returns the members who completed the given part of a day, ordered by who earned
+// the star first. Members with identical timestamps are ordered by ID.
+func DailyRanking(members []Member, day, part int) []Member {
+	ranked := FilterMembers(members, func(m Member) bool { return m.CompletedPart(day, part) })
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, _ := ranked[i].level(day, part)
+		b, _ := ranked[j].level(day, part)
+		if !a.Timestamp.Equal(b.Timestamp.Time) {
+			return a.Timestamp.Before(b.Timestamp.Time)
+		}
+		return idLess(ranked[i].ID, ranked[j].ID)
+	})
+	return ranked
+}
+
+// SpeedRanking returns the members who completed the given part of a day in year, ordered by
+// SolveDuration, fastest first. Members with identical solve times are ordered by ID.
+func SpeedRanking(members []Member, day, part, year int) []Member {
+	return SpeedRankingIn(members, day, part, year, eastern)
+}
+
+// SpeedRankingIn is like SpeedRanking but measures solve times from midnight in loc, see
+// SolveDurationIn.
+func SpeedRankingIn(members []Member, day, part, year int, loc *time.Location) []Member {
+	ranked := FilterMembers(members, func(m Member) bool { return m.CompletedPart(day, part) })
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, _ := ranked[i].SolveDurationIn(day, part, year, loc)
+		b, _ := ranked[j].SolveDurationIn(day, part, year, loc)
+		if a != b {
+			return a < b
+		}
+		return idLess(ranked[i].ID, ranked[j].ID)
+	})
+	return ranked
+}
+
+// AssignRanks sorts a copy of members by sorted and ranks them using standard competition ranking:
+// members tying on the sort key share a rank, and the ranks after them are skipped accordingly
+// (1, 2, 2, 4). With NoSort members are ordered by ID and every member gets its own rank.
+func AssignRanks(members []Member, sorted LeaderboardSort) []RankedMember {
+	sortedMembers := make([]Member, len(members))
+	copy(sortedMembers, members)
+	sortBy(sortedMembers, sorted)
+
+	ranked := make([]RankedMember, len(sortedMembers))
+	for i, m := range sortedMembers {
+		rank := i + 1
+		if i > 0 && sameRank(sortedMembers[i-1], m, sorted) {
+			rank = ranked[i-1].Rank
+		}
+		ranked[i] = RankedMember{Rank: rank, Member: m}
+	}
+	return ranked
+}
+
+// sameRank reports whether a and b tie on the key of the given sort.
+func sameRank(a, b Member, sorted LeaderboardSort) bool {
+	switch sorted {
+	case SortByLocalScore, SortByLocalScoreAsc:
+		return a.LocalScore == b.LocalScore
+	case SortByGlobalScore, SortByGlobalScoreAsc:
+		return a.GlobalScore == b.GlobalScore
+	case SortByStars, SortByStarsAsc:
+		return a.Stars == b.Stars
+	case SortByName:
+		return strings.EqualFold(a.Name, b.Name)
+	case SortByLastStar, SortByLastStarAsc:
+		return a.LastStarTS.Equal(b.LastStarTS.Time) || a.LastStarTS.IsZero() && b.LastStarTS.IsZero()
+	}
+	return false
+}
+
+// MemberPercentile returns the standing of the member with the given ID as the percentage of the
+// leaderboard ranked before or at the member's rank by sorted, so 15 means the member is in the
+// top 15%. Tied members share a rank and therefore a percentile. It returns false if the member is
+// not found.
+func MemberPercentile(members []Member, id string, sorted LeaderboardSort) (float64, bool) {
+	for _, rm := range AssignRanks(members, sorted) {
+		if rm.ID == id {
+			return float64(rm.Rank) / float64(len(members)) * 100, true
+		}
+	}
+	return 0, false
+}
+
+// MembersAtRank returns the members who share the given rank, as assigned by AssignRanks, e.g. the
+// joint leaders for rank 1. The result is empty, not nil, if no member has that rank.
+func MembersAtRank(members []Member, rank int, sorted LeaderboardSort) []Member {
+	atRank := []Member{}
+	for _, rm := range AssignRanks(members, sorted) {
+		if rm.Rank == rank {
+			atRank = append(atRank, rm.Member)
+		}
+	}
+	return atRank
+}
+
+// RankHistory returns the rank, as assigned by AssignRanks, of the member with the given ID in
+// each of the snapshots, or -1 for snapshots the member is not part of.
+func RankHistory(snapshots [][]Member, id string, sorted LeaderboardSort) []int {
+	history := make([]int, len(snapshots))
+	for i, members := range snapshots {
+		history[i] = -1
+		for _, rm := range AssignRanks(members, sorted) {
+			if rm.ID == id {
+				history[i] = rm.Rank
+				break
+			}
+		}
+	}
+	return history
+}
+
+// GapToNextRank returns how many points, by the numeric key of sorted (local score, global score
+// or stars), the member with the given ID needs to tie the member ranked directly above them; one
+// more overtakes them. It returns false if the member is not found, already ranks first, or sorted
+// does not sort by a number, highest first.
+func GapToNextRank(members []Member, id string, sorted LeaderboardSort) (int, bool) {
+	ranked := AssignRanks(members, sorted)
+	for _, rm := range ranked {
+		if rm.ID != id {
+			continue
+		}
+		if rm.Rank == 1 {
+			return 0, false
+		}
+		// Ranks skip over ties, so the member just before this rank ranks directly above.
+		above, ok := scoreKey(ranked[rm.Rank-2].Member, sorted)
+		own, _ := scoreKey(rm.Member, sorted)
+		return above - own, ok
+	}
+	return 0, false
+}
+
+// scoreKey returns the numeric value members are sorted by, if sorted sorts by a number.
+func scoreKey(m Member, sorted LeaderboardSort) (int, bool) {
+	switch sorted {
+	case SortByLocalScore:
+		return m.LocalScore, true
+	case SortByGlobalScore:
+		return m.GlobalScore, true
+	case SortByStars:
+		return m.Stars, true
+	}
+	return 0, false
+}
@@ -0,0 +1,54 @@
+package leaderboard
+
+// ComputeLocalScores recomputes the local score of every member, by ID, from their completion
+// timestamps. Like Advent of Code, every part of every day awards the first member to complete it
+// as many points as there are members, the second one point less, and so on. Members completing a
+// part at the same second are ordered by ID.
+func ComputeLocalScores(members []Member) map[string]int {
+	scores := make(map[string]int, len(members))
+	for id, days := range LocalScoreBreakdown(members) {
+		total := 0
+		for _, points := range days {
+			total += points
+		}
+		scores[id] = total
+	}
+	return scores
+}
+
+// LocalScoreBreakdown returns the local score points of every member, by ID, per day, computed
+// like ComputeLocalScores so that the points of a member add up to their computed local score.
+// Members without any points map to an empty breakdown.
+func LocalScoreBreakdown(members []Member) map[string]map[int]int {
+	points := localPoints(members)
+	for _, m := range members {
+		if points[m.ID] == nil {
+			points[m.ID] = make(map[int]int)
+		}
+	}
+	return points
+}
+
+// localPoints returns the local score points of every member, by ID and day.
+func localPoints(members []Member) map[string]map[int]int {
+	type dayPart struct{ day, part int }
+	finishers := make(map[dayPart][]StarEvent)
+	for _, m := range members {
+		for _, e := range m.starEvents() {
+			k := dayPart{e.Day, e.Part}
+			finishers[k] = append(finishers[k], e)
+		}
+	}
+
+	points := make(map[string]map[int]int)
+	for k, events := range finishers {
+		sortStarEvents(events)
+		for rank, e := range events {
+			if points[e.MemberID] == nil {
+				points[e.MemberID] = make(map[int]int)
+			}
+			points[e.MemberID][k.day] += len(members) - rank
+		}
+	}
+	return points
+}
@@ -0,0 +1,23 @@
+package leaderboard
+
+import "testing"
+
+func TestComputeLocalScores(t *testing.T) {
+	lb, err := ParseLeaderboardBytes(readFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"101": 14, "202": 9, "33": 3, "44": 0, "55": 0}
+	got := ComputeLocalScores(lb.MemberSlice(NoSort))
+	if len(got) != len(want) {
+		t.Errorf("ComputeLocalScores returned %d scores, want %d", len(got), len(want))
+	}
+	for id, score := range want {
+		if got[id] != score {
+			t.Errorf("ComputeLocalScores()[%q] = %d, want %d", id, got[id], score)
+		}
+		if m := lb.Members[id]; m.LocalScore != score {
+			t.Errorf("fixture local score of %q = %d, want %d", id, m.LocalScore, score)
+		}
+	}
+}
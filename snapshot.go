@@ -0,0 +1,40 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// Snapshot is a Leaderboard as saved by SaveSnapshot, together with the time it was taken.
+type Snapshot struct {
+	Taken       time.Time    `json:"taken"`
+	Leaderboard *Leaderboard `json:"leaderboard"`
+}
+
+// SaveSnapshot writes lb to w as JSON, recording the current time as the time it was taken.
+func SaveSnapshot(w io.Writer, lb *Leaderboard) error {
+	return json.NewEncoder(w).Encode(Snapshot{Taken: time.Now(), Leaderboard: lb})
+}
+
+// LoadSnapshot reads a Leaderboard saved by SaveSnapshot from r.
+func LoadSnapshot(r io.Reader) (*Leaderboard, error) {
+	s, err := ReadSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Leaderboard, nil
+}
+
+// ReadSnapshot is like LoadSnapshot but also returns the time the snapshot was taken.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.Leaderboard == nil {
+		return nil, errors.New("snapshot does not contain a leaderboard")
+	}
+	return &s, nil
+}
@@ -0,0 +1,327 @@
+package leaderboard
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PerDayStarCounts returns, per day, how many members earned the star for part 1 and for part 2 of
+// that day, as {part1Count, part2Count}. Days on which nobody earned a star are omitted.
+func PerDayStarCounts(members []Member) map[int][2]int {
+	counts := make(map[int][2]int)
+	for _, m := range members {
+		for d := range m.Days {
+			day, err := strconv.Atoi(d)
+			if err != nil {
+				continue
+			}
+			c := counts[day]
+			if m.CompletedPart(day, 1) {
+				c[0]++
+			}
+			if m.CompletedPart(day, 2) {
+				c[1]++
+			}
+			if c != [2]int{} {
+				counts[day] = c
+			}
+		}
+	}
+	return counts
+}
+
+// StarHistogram maps each star total to the number of members with that many stars, including
+// members without any stars.
+func StarHistogram(members []Member) map[int]int {
+	histogram := make(map[int]int)
+	for _, m := range members {
+		histogram[m.Stars]++
+	}
+	return histogram
+}
+
+// AverageLocalScore returns the mean local score of the members, or 0 if there are none.
+func AverageLocalScore(members []Member) float64 {
+	return average(localScores(members))
+}
+
+// MedianLocalScore returns the median local score of the members, or 0 if there are none.
+func MedianLocalScore(members []Member) float64 {
+	return median(localScores(members))
+}
+
+// AverageStars returns the mean number of stars of the members, or 0 if there are none.
+func AverageStars(members []Member) float64 {
+	return average(stars(members))
+}
+
+// MedianStars returns the median number of stars of the members, or 0 if there are none.
+func MedianStars(members []Member) float64 {
+	return median(stars(members))
+}
+
+func localScores(members []Member) []int {
+	scores := make([]int, len(members))
+	for i, m := range members {
+		scores[i] = m.LocalScore
+	}
+	return scores
+}
+
+func stars(members []Member) []int {
+	stars := make([]int, len(members))
+	for i, m := range members {
+		stars[i] = m.Stars
+	}
+	return stars
+}
+
+func average(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return float64(sum) / float64(len(values))
+}
+
+// median returns the median of values, sorting them in place.
+func median(values []int) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	sort.Ints(values)
+	if n%2 == 1 {
+		return float64(values[n/2])
+	}
+	return float64(values[n/2-1]+values[n/2]) / 2
+}
+
+// MergeByMember combines the members of several years, keyed by year, into all-time standings.
+// Stars and scores of members with the same ID are summed, and the name and last star time are
+// taken from the most recent year. Per-day completions cannot be combined across years, so Days is
+// left empty. Members are returned ordered by ID.
+func MergeByMember(perYear map[int][]Member) []Member {
+	years := make([]int, 0, len(perYear))
+	for year := range perYear {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	merged := make(map[string]*Member)
+	for _, year := range years {
+		for _, m := range perYear[year] {
+			total, ok := merged[m.ID]
+			if !ok {
+				total = &Member{ID: m.ID}
+				merged[m.ID] = total
+			}
+			total.Name = m.Name
+			total.Stars += m.Stars
+			total.LocalScore += m.LocalScore
+			total.GlobalScore += m.GlobalScore
+			if m.LastStarTS.After(total.LastStarTS.Time) {
+				total.LastStarTS = m.LastStarTS
+			}
+		}
+	}
+
+	members := make([]Member, 0, len(merged))
+	for _, m := range merged {
+		members = append(members, *m)
+	}
+	sort.Slice(members, func(i, j int) bool { return idLess(members[i].ID, members[j].ID) })
+	return members
+}
+
+// CompletionMatrix returns, for each member in order, the number of stars earned on each day from
+// 1 up to maxDay. If maxDay is zero or negative, the highest day on which any member earned a star
+// is used instead.
+func CompletionMatrix(members []Member, maxDay int) [][]int {
+	if maxDay <= 0 {
+		maxDay = lastCompletedDay(members)
+	}
+	matrix := make([][]int, len(members))
+	for i, m := range members {
+		row := make([]int, maxDay)
+		for day := 1; day <= maxDay; day++ {
+			row[day-1] = m.StarsOnDay(day)
+		}
+		matrix[i] = row
+	}
+	return matrix
+}
+
+// CumulativeStarsByDay returns, for each day from 1 up to maxDay, the total number of stars the
+// members had earned when the next day's puzzle unlocked, whichever puzzles they were for. The
+// event is the one in which the earliest star was earned, as the leaderboard JSON does not record
+// its year. Stars earned after day 25 still count towards the event, so day 25 includes every star
+// and any days after it repeat that total. If maxDay is zero or negative, the day of the most
+// recent star is used instead.
+func CumulativeStarsByDay(members []Member, maxDay int) []int {
+	var earned []time.Time
+	for _, m := range members {
+		for _, e := range m.Timeline() {
+			earned = append(earned, e.Timestamp)
+		}
+	}
+	sort.Slice(earned, func(i, j int) bool { return earned[i].Before(earned[j]) })
+	year := 0
+	if len(earned) > 0 {
+		year = eventYear(earned[0])
+	}
+	if maxDay <= 0 {
+		maxDay = lastCompletedDay(members)
+		if len(earned) > 0 {
+			if day, _ := EventProgress(year, earned[len(earned)-1]); day > maxDay {
+				maxDay = day
+			}
+		}
+	}
+
+	totals := make([]int, maxDay)
+	total := 0
+	for day := 1; day <= maxDay; day++ {
+		for total < len(earned) && (day >= 25 || earned[total].Before(PuzzleUnlock(year, day+1))) {
+			total++
+		}
+		totals[day-1] = total
+	}
+	return totals
+}
+
+// eventYear returns the year of the event during or after which a star earned at t was earned:
+// stars earned before December belong to the event of the previous year.
+func eventYear(t time.Time) int {
+	t = t.In(eastern)
+	if t.Month() < time.December {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// FirstBlood returns, for each day from 1 up to maxDay, the member who earned the first star of
+// that day before anyone else. Days on which nobody earned it are absent. Ties go to the member
+// with the lowest ID. If maxDay is zero or negative, the highest day on which any member earned a
+// star is used.
+func FirstBlood(members []Member, maxDay int) map[int]Member {
+	if maxDay <= 0 {
+		maxDay = lastCompletedDay(members)
+	}
+	first := map[int]Member{}
+	for day := 1; day <= maxDay; day++ {
+		var earliest time.Time
+		for _, m := range members {
+			l, ok := m.level(day, 1)
+			if !ok || l.Timestamp.IsZero() {
+				continue
+			}
+			best, found := first[day]
+			if !found || l.Timestamp.Before(earliest) || l.Timestamp.Equal(earliest) && idLess(m.ID, best.ID) {
+				first[day], earliest = m, l.Timestamp.Time
+			}
+		}
+	}
+	return first
+}
+
+// ProjectFinalStars extrapolates, per member ID, how many stars each member will have at the end
+// of the event if they keep the pace of their first currentDay days. currentDay is clamped to the
+// range 1 to 25; members without stars project zero.
+func ProjectFinalStars(members []Member, currentDay int) map[string]float64 {
+	if currentDay < 1 {
+		currentDay = 1
+	}
+	if currentDay > 25 {
+		currentDay = 25
+	}
+	projected := make(map[string]float64, len(members))
+	for _, m := range members {
+		projected[m.ID] = float64(m.Stars) / float64(currentDay) * 25
+	}
+	return projected
+}
+
+// ActiveDays returns, in ascending order, the days on which at least one of the members earned a
+// star. The result is empty, not nil, if nobody did.
+func ActiveDays(members []Member) []int {
+	seen := make(map[int]bool)
+	days := []int{}
+	for _, m := range members {
+		for _, day := range m.activeDays() {
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sort.Ints(days)
+	return days
+}
+
+// SolveDurations returns the SolveDuration of the given part of a day in year for every member who
+// completed it, in the order of members. The result is empty, not nil, if nobody did.
+func SolveDurations(members []Member, day, part, year int) []time.Duration {
+	return SolveDurationsIn(members, day, part, year, eastern)
+}
+
+// SolveDurationsIn is like SolveDurations but measures from midnight in loc, see SolveDurationIn.
+func SolveDurationsIn(members []Member, day, part, year int, loc *time.Location) []time.Duration {
+	durations := []time.Duration{}
+	for _, m := range members {
+		if d, ok := m.SolveDurationIn(day, part, year, loc); ok {
+			durations = append(durations, d)
+		}
+	}
+	return durations
+}
+
+// lastCompletedDay returns the highest day on which any of the members earned a star, or 0.
+func lastCompletedDay(members []Member) int {
+	last := 0
+	for _, m := range members {
+		for d, levels := range m.Days {
+			if day, err := strconv.Atoi(d); err == nil && len(levels) > 0 && day > last {
+				last = day
+			}
+		}
+	}
+	return last
+}
+
+// Summary bundles the aggregates commonly shown in the header of a leaderboard report.
+type Summary struct {
+	TotalMembers     int
+	TotalStars       int
+	AverageStars     float64
+	MedianLocalScore float64
+	TopMember        Member    // highest local score, ties going to the lowest ID
+	MostRecentStar   time.Time // zero if nobody earned a star
+}
+
+// Summarize returns the Summary of the members. It returns the zero Summary if there are none.
+func Summarize(members []Member) Summary {
+	if len(members) == 0 {
+		return Summary{}
+	}
+	ranked := append([]Member(nil), members...)
+	sortBy(ranked, SortByLocalScore)
+
+	s := Summary{
+		TotalMembers:     len(members),
+		TotalStars:       CountTotalStars(members),
+		AverageStars:     AverageStars(members),
+		MedianLocalScore: MedianLocalScore(members),
+		TopMember:        ranked[0],
+	}
+	for _, m := range members {
+		if !m.LastStarTS.IsZero() && m.LastStarTS.After(s.MostRecentStar) {
+			s.MostRecentStar = m.LastStarTS.Time
+		}
+	}
+	return s
+}
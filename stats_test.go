@@ -0,0 +1,22 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCumulativeStarsByDay(t *testing.T) {
+	lb, err := ParseLeaderboardBytes(readFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Carol earned her only star, for day 1, on day 11 of the event.
+	want := []int{4, 5, 5, 5, 5, 5, 5, 5, 5, 5, 6}
+	if got := CumulativeStarsByDay(lb.MemberSlice(NoSort), 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("CumulativeStarsByDay = %v, want %v", got, want)
+	}
+	// Day 25 counts every star, and later days repeat its total.
+	if got := CumulativeStarsByDay(lb.MemberSlice(NoSort), 27); len(got) != 27 || got[24] != 6 || got[26] != 6 {
+		t.Errorf("CumulativeStarsByDay up to day 27 = %v, want 6 stars from day 25", got)
+	}
+}